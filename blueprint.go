@@ -3,6 +3,9 @@ package schema
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
 )
 
@@ -17,6 +20,11 @@ type Blueprint struct {
 	commands []*Command // The commands that should be run for the table.
 	config   *Config
 	ctx      context.Context
+	schema   *Schema
+
+	sqliteTable    *sqliteTable  // The current sqlite table definition, used to rebuild the table.
+	version        string        // The database server version, inspected before compiling.
+	currentColumns []*ColumnInfo // The current columns of the table, inspected before compiling.
 }
 
 // NewBlueprint generate blueprint
@@ -30,6 +38,7 @@ func NewBlueprint(schema *Schema, table string, callback ...func(b *Blueprint))
 		table:  table,
 		config: schema.config,
 		ctx:    schema.ctx,
+		schema: schema,
 	}
 
 	if len(callback) > 0 {
@@ -47,6 +56,7 @@ func (b *Blueprint) AddColumn(types string, name string, parameters ...Map) (col
 		Type:       types,
 		Name:       name,
 		Attributes: params,
+		blueprint:  b,
 	}
 
 	b.columns = append(b.columns, column)
@@ -72,27 +82,178 @@ func (b *Blueprint) Rename(to string) *Command {
 	})
 }
 
+// ReorderColumns Move the given columns to the beginning of the table in order, the other columns keep
+// their order after them, mysql only. Only the columns which are out of place are moved by the current definitions.
+func (b *Blueprint) ReorderColumns(columns ...string) *Command {
+	return b.addCommand(commandReorderColumns, Map{
+		commandAttrColumns: columns,
+	})
+}
+
+// After Add the columns of the callback after the given column in order, mysql only.
+func (b *Blueprint) After(column string, callback func(table *Blueprint)) {
+	start := len(b.columns)
+
+	callback(b)
+
+	for _, added := range b.columns[start:] {
+		added.After(column)
+		column = added.Name
+	}
+}
+
 // RenameColumn Indicate that the given columns should be renamed.
-// func (b *Blueprint) RenameColumn(from, to string) *Command {
-// 	return b.addCommand(commandRenameColumn, Map{
-// 		commandAttrFrom: from,
-// 		commandAttrTo:   to,
-// 	})
-// }
+func (b *Blueprint) RenameColumn(from, to string) *Command {
+	return b.addCommand(commandRenameColumn, Map{
+		commandAttrFrom: from,
+		commandAttrTo:   to,
+	})
+}
 
 // Primary add primary index
-func (b *Blueprint) Primary(columns interface{}, algorithm ...string) {
-	b.indexCommand(commandPrimary, columns, algorithm...)
+func (b *Blueprint) Primary(columns interface{}, algorithm ...string) *IndexDefinition {
+	return &IndexDefinition{command: b.indexCommand(commandPrimary, columns, algorithm...)}
 }
 
-// Unique add unique column
-func (b *Blueprint) Unique(columns interface{}, algorithm ...string) {
-	b.indexCommand(commandUnique, columns, algorithm...)
+// Unique add unique column, e.g. table.Unique("email").Name("uk_email")
+func (b *Blueprint) Unique(columns interface{}, algorithm ...string) *IndexDefinition {
+	return &IndexDefinition{command: b.indexCommand(commandUnique, columns, algorithm...)}
 }
 
 // Index add index
-func (b *Blueprint) Index(columns interface{}, algorithm ...string) {
-	b.indexCommand(commandIndex, columns, algorithm...)
+func (b *Blueprint) Index(columns interface{}, algorithm ...string) *IndexDefinition {
+	return &IndexDefinition{command: b.indexCommand(commandIndex, columns, algorithm...)}
+}
+
+// Fulltext add fulltext index, e.g. table.Fulltext([]string{"title", "body"}, schema.WithParser("ngram"))
+func (b *Blueprint) Fulltext(columns interface{}, options ...IndexOption) *IndexDefinition {
+	definition := &IndexDefinition{}
+	if column := toStrings(columns); column != nil {
+		definition.command = b.addCommand(commandFulltext, Map{
+			commandAttrIndex:   b.createIndexName(commandFulltext, column),
+			commandAttrColumns: column,
+		})
+		for _, option := range options {
+			option(definition.command)
+		}
+	}
+	return definition
+}
+
+// SpatialIndex add spatial index, the columns must be not null spatial columns
+func (b *Blueprint) SpatialIndex(columns interface{}) *IndexDefinition {
+	return &IndexDefinition{command: b.indexCommand(commandSpatialIndex, columns)}
+}
+
+// DropPrimary Indicate that the given primary key should be dropped,
+// index is the index name string or the columns []string of the primary key
+func (b *Blueprint) DropPrimary(index ...interface{}) *Command {
+	return b.dropIndexCommand(commandDropPrimary, commandPrimary, varDef(index))
+}
+
+// DropUnique Indicate that the given unique key should be dropped,
+// index is the index name string or the columns []string of the unique key
+func (b *Blueprint) DropUnique(index interface{}) *Command {
+	return b.dropIndexCommand(commandDropUnique, commandUnique, index)
+}
+
+// DropIndex Indicate that the given index should be dropped,
+// index is the index name string or the columns []string of the index
+func (b *Blueprint) DropIndex(index interface{}) *Command {
+	return b.dropIndexCommand(commandDropIndex, commandIndex, index)
+}
+
+// DropFulltext Indicate that the given fulltext index should be dropped,
+// index is the index name string or the columns []string of the index
+func (b *Blueprint) DropFulltext(index interface{}) *Command {
+	return b.dropIndexCommand(commandDropFulltext, commandFulltext, index)
+}
+
+// DropSpatialIndex Indicate that the given spatial index should be dropped,
+// index is the index name string or the columns []string of the index
+func (b *Blueprint) DropSpatialIndex(index interface{}) *Command {
+	return b.dropIndexCommand(commandDropSpatialIndex, commandSpatialIndex, index)
+}
+
+// RenameIndex Indicate that the given index should be renamed
+func (b *Blueprint) RenameIndex(from, to string) *Command {
+	return b.addCommand(commandRenameIndex, Map{
+		commandAttrFrom: from,
+		commandAttrTo:   to,
+	})
+}
+
+// Check add a check constraint, e.g. table.Check("users_age_check", "`age` >= 18")
+func (b *Blueprint) Check(name string, expression string) *Command {
+	return b.addCommand(commandCheck, Map{
+		commandAttrIndex:      name,
+		commandAttrExpression: expression,
+	})
+}
+
+// DropCheck drop the check constraint
+func (b *Blueprint) DropCheck(name string) *Command {
+	return b.addCommand(commandDropCheck, Map{
+		commandAttrIndex: name,
+	})
+}
+
+// Foreign add foreign key, columns can string or []string
+func (b *Blueprint) Foreign(columns interface{}) *ForeignKeyDefinition {
+	cols := toStrings(columns)
+
+	return &ForeignKeyDefinition{
+		command: b.addCommand(commandForeign, Map{
+			commandAttrIndex:      b.createIndexName(commandForeign, cols),
+			commandAttrColumns:    cols,
+			commandAttrReferences: []string{"id"},
+		}),
+	}
+}
+
+// DropForeign Indicate that the given foreign key should be dropped,
+// index is the constraint name string or the columns []string of the foreign key
+func (b *Blueprint) DropForeign(index interface{}) *Command {
+	return b.dropIndexCommand(commandDropForeign, commandForeign, index)
+}
+
+// ForeignId add unsigned bigint foreign key column, use Constrained to add the constraint
+func (b *Blueprint) ForeignId(column string) *Column {
+	return b.UnsignedBigInt(column)
+}
+
+// ForeignUuid add uuid foreign key column, use Constrained to add the constraint
+func (b *Blueprint) ForeignUuid(column string) *Column {
+	return b.Uuid(column)
+}
+
+// ForeignIdFor add foreign key column for model, the column default {model}_id,
+// it is a uuid column when the ID field of model is a string,
+// Constrained references the table of model
+func (b *Blueprint) ForeignIdFor(model interface{}, column ...string) *Column {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var (
+		name  = varDef(column, snake(t.Name())+"_id")
+		field reflect.StructField
+		col   *Column
+	)
+
+	if t.Kind() == reflect.Struct {
+		field, _ = t.FieldByNameFunc(func(s string) bool { return strings.EqualFold(s, "id") })
+	}
+
+	if field.Type != nil && field.Type.Kind() == reflect.String {
+		col = b.ForeignUuid(name)
+	} else {
+		col = b.ForeignId(name)
+	}
+	col.Attributes[ColumnAttrOn] = modelTable(model)
+
+	return col
 }
 
 // Id id primary
@@ -193,6 +354,14 @@ func (b *Blueprint) getAutoIncrementAndUnsignedMap(autoIncrementAndUnsigned ...b
 	}
 }
 
+// getPrecisionMap get the attributes of the fractional seconds precision, 0 is the database default
+func (b *Blueprint) getPrecisionMap(precision ...int) Map {
+	if p := varDef(precision, 0); p > 0 {
+		return Map{ColumnAttrPrecision: p}
+	}
+	return Map{}
+}
+
 // Boolean add tinyint(1) column
 func (b *Blueprint) Boolean(column string) *Column {
 	return b.AddColumn(ColumnTypeBoolean, column)
@@ -279,43 +448,116 @@ func (b *Blueprint) Date(column string) *Column {
 	return b.AddColumn(ColumnTypeDate, column)
 }
 
-func (b *Blueprint) DateTime(column string) *Column {
-	return b.AddColumn(ColumnTypeDateTime, column)
+// DateTime add datetime column, precision is the fractional seconds precision
+func (b *Blueprint) DateTime(column string, precision ...int) *Column {
+	return b.AddColumn(ColumnTypeDateTime, column, b.getPrecisionMap(precision...))
 }
 
-func (b *Blueprint) Time(column string) *Column {
-	return b.AddColumn(ColumnTypeTime, column)
+// Time add time column, precision is the fractional seconds precision
+func (b *Blueprint) Time(column string, precision ...int) *Column {
+	return b.AddColumn(ColumnTypeTime, column, b.getPrecisionMap(precision...))
 }
 
-func (b *Blueprint) Timestamp(column string) *Column {
-	return b.AddColumn(ColumnTypeTimestamp, column)
+// Timestamp add timestamp column, precision is the fractional seconds precision
+func (b *Blueprint) Timestamp(column string, precision ...int) *Column {
+	return b.AddColumn(ColumnTypeTimestamp, column, b.getPrecisionMap(precision...))
 }
 
-func (b *Blueprint) Timestamps() {
-	b.Timestamp("created_at").Nullable()
-	b.Timestamp("updated_at").Nullable()
+// Timestamps add nullable created_at and updated_at columns
+func (b *Blueprint) Timestamps(precision ...int) {
+	b.Timestamp("created_at", precision...).Nullable()
+	b.Timestamp("updated_at", precision...).Nullable()
 }
 
-func (b *Blueprint) SoftDeletes() *Column {
-	return b.Timestamp("deleted_at").Nullable()
+// SoftDeletes add nullable deleted_at column
+func (b *Blueprint) SoftDeletes(precision ...int) *Column {
+	return b.Timestamp("deleted_at", precision...).Nullable()
 }
 
 func (b *Blueprint) Year(column string) *Column {
 	return b.AddColumn(ColumnTypeYear, column)
 }
 
-func (b *Blueprint) Binary(column string) *Column {
+// Binary add binary column, the optional length is the fixed byte length, e.g. binary(16)
+func (b *Blueprint) Binary(column string, length ...int) *Column {
+	if len(length) > 0 {
+		return b.AddColumn(ColumnTypeBinary, column, Map{ColumnAttrLength: length[0]})
+	}
 	return b.AddColumn(ColumnTypeBinary, column)
 }
 
+// VarBinary add varbinary column
+func (b *Blueprint) VarBinary(column string, length int) *Column {
+	return b.AddColumn(ColumnTypeVarBinary, column, Map{ColumnAttrLength: length})
+}
+
+// TinyBlob add tinyblob column
+func (b *Blueprint) TinyBlob(column string) *Column {
+	return b.AddColumn(ColumnTypeTinyBlob, column)
+}
+
+// Blob add blob column
 func (b *Blueprint) Blob(column string) *Column {
-	return b.AddColumn(ColumnTypeBinary, column)
+	return b.AddColumn(ColumnTypeBlob, column)
+}
+
+// MediumBlob add mediumblob column
+func (b *Blueprint) MediumBlob(column string) *Column {
+	return b.AddColumn(ColumnTypeMediumBlob, column)
+}
+
+// LongBlob add longblob column
+func (b *Blueprint) LongBlob(column string) *Column {
+	return b.AddColumn(ColumnTypeLongBlob, column)
 }
 
 func (b *Blueprint) Uuid(column string) *Column {
 	return b.AddColumn(ColumnTypeUuid, column)
 }
 
+// Geometry add geometry column, srid is the spatial reference system identifier, e.g. 4326
+func (b *Blueprint) Geometry(column string, srid ...int) *Column {
+	return b.AddColumn(ColumnTypeGeometry, column, b.getSridMap(srid...))
+}
+
+// Point add point column, srid is the spatial reference system identifier
+func (b *Blueprint) Point(column string, srid ...int) *Column {
+	return b.AddColumn(ColumnTypePoint, column, b.getSridMap(srid...))
+}
+
+// LineString add linestring column, srid is the spatial reference system identifier
+func (b *Blueprint) LineString(column string, srid ...int) *Column {
+	return b.AddColumn(ColumnTypeLineString, column, b.getSridMap(srid...))
+}
+
+// Polygon add polygon column, srid is the spatial reference system identifier
+func (b *Blueprint) Polygon(column string, srid ...int) *Column {
+	return b.AddColumn(ColumnTypePolygon, column, b.getSridMap(srid...))
+}
+
+// MultiPoint add multipoint column, srid is the spatial reference system identifier
+func (b *Blueprint) MultiPoint(column string, srid ...int) *Column {
+	return b.AddColumn(ColumnTypeMultiPoint, column, b.getSridMap(srid...))
+}
+
+// MultiPolygon add multipolygon column, srid is the spatial reference system identifier
+func (b *Blueprint) MultiPolygon(column string, srid ...int) *Column {
+	return b.AddColumn(ColumnTypeMultiPolygon, column, b.getSridMap(srid...))
+}
+
+// GeometryCollection add geometrycollection column, srid is the spatial reference system identifier
+func (b *Blueprint) GeometryCollection(column string, srid ...int) *Column {
+	return b.AddColumn(ColumnTypeGeometryCollection, column, b.getSridMap(srid...))
+}
+
+// getSridMap get the attributes of the spatial reference system identifier, no srid by default
+func (b *Blueprint) getSridMap(srid ...int) Map {
+	if len(srid) > 0 {
+		return Map{ColumnAttrSrid: srid[0]}
+	}
+	return Map{}
+}
+
 // Comment add comment
 func (b *Blueprint) Comment(comment string) {
 	b.addCommand(commandComment, Map{
@@ -343,6 +585,30 @@ func (b *Blueprint) GetCommands() []*Command {
 	return b.commands
 }
 
+// findColumn find the column by name
+func (b *Blueprint) findColumn(name string) *Column {
+	for _, column := range b.columns {
+		if column.Name == name {
+			return column
+		}
+	}
+	return nil
+}
+
+// mergeChangedColumns Merge the current column definitions into the changed columns
+func (b *Blueprint) mergeChangedColumns() {
+	for _, column := range b.getChangedColumns() {
+		if current := b.currentColumn(column.Name); current != nil {
+			column.merge(current)
+		}
+	}
+}
+
+// currentColumn find the current column inspected before compiling
+func (b *Blueprint) currentColumn(name string) *ColumnInfo {
+	return findColumn(b.currentColumns, name)
+}
+
 // getAddedColumns get added columns
 func (b *Blueprint) getAddedColumns() (columns []*Column) {
 	return filter(b.columns, func(v *Column) bool {
@@ -357,37 +623,216 @@ func (b *Blueprint) getChangedColumns() []*Column {
 	})
 }
 
-// build exec sql
-func (b *Blueprint) build(grammar *Grammar) (err error) {
-	if b.config.DB == nil {
+// build exec sql, or collect sql when the schema is pretending
+func (b *Blueprint) build(grammar Grammar) (err error) {
+	if b.config.DB == nil && !b.schema.pretending {
 		return errors.New("DB is nil")
 	}
 
-	statements := b.ToSql(grammar)
+	b.addImpliedCommands()
+
+	// the changed columns keep the attributes of the current columns, the reordered columns are moved by them
+	if !b.creating() && (len(b.getChangedColumns()) > 0 || b.hasCommand(commandReorderColumns)) &&
+		b.currentColumns == nil && b.config.DB != nil {
+		if b.currentColumns, err = b.schema.GetColumns(b.table); err != nil {
+			return err
+		}
+	}
 
-	for _, statement := range statements {
-		_, err = b.config.DB.ExecContext(b.ctx, statement)
-		if err != nil {
+	if p, ok := grammar.(preparer); ok {
+		if err = p.prepare(b); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	statements, err := b.toStatements(grammar)
+	if err != nil {
+		return err
+	}
+
+	if b.schema.pretending {
+		b.schema.statements = append(b.schema.statements, statements...)
+		return nil
+	}
+
+	return b.schema.exec(b.ctx, statements)
+}
+
+// ToSql Get the raw SQL statements for the blueprint,
+// an error is returned when the grammar can not compile a command.
+func (b *Blueprint) ToSql(grammar Grammar) ([]string, error) {
+	statements, err := b.toStatements(grammar)
+	if err != nil {
+		return nil, err
+	}
+
+	var sql []string
+	for _, statement := range statements {
+		sql = append(sql, statement.SQL)
+	}
+
+	return sql, nil
 }
 
-// ToSql Get the raw SQL statements for the blueprint.
-func (b *Blueprint) ToSql(grammar *Grammar) []string {
+// toStatements Compile the commands into statements with the table, command and columns of each statement.
+func (b *Blueprint) toStatements(grammar Grammar) (statements []*Statement, err error) {
 	b.addImpliedCommands()
+	b.mergeChangedColumns()
+
+	if err = b.validate(); err != nil {
+		return nil, err
+	}
+
+	if v, ok := grammar.(validator); ok {
+		if err = v.validate(b); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		compiled = map[string]bool{}
+		last     []*Statement // the statements of the last compiled command
+	)
+
+	for _, command := range b.commands {
+		var (
+			sql         []string
+			destructive = b.destructive(command)
+			columns     = b.commandColumns(command)
+		)
+
+		// the rebuild drops and recreates the table, e.g. sqlite changes a column by rebuilding the table
+		if r, ok := grammar.(rebuilder); ok {
+			if _, first := r.rebuilding(b, command); first {
+				destructive = true
+			}
+		}
+
+		if sql, err = grammar.Compile(b, command); err != nil {
+			return nil, err
+		}
+
+		// the command is compiled into the statements of an earlier command, e.g. the sqlite table rebuild
+		if len(sql) == 0 {
+			for _, statement := range last {
+				statement.Destructive = statement.Destructive || destructive
+				statement.Columns = unique(append(statement.Columns, columns...))
+			}
+			continue
+		}
+
+		last = nil
+		for _, s := range sql {
+			if compiled[s] {
+				continue
+			}
+			compiled[s] = true
+
+			statement := &Statement{
+				Table:       b.table,
+				Command:     command.Name,
+				Destructive: destructive,
+				Columns:     append([]string(nil), columns...),
+				SQL:         s,
+			}
+			statements = append(statements, statement)
+			last = append(last, statement)
+		}
+	}
+
+	return statements, nil
+}
+
+// keywordPattern match the charsets, engines and fulltext parsers which are compiled unquoted
+var keywordPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// validate check the charsets, engines, index algorithms, foreign key actions and fulltext parsers,
+// which are keywords compiled into the statements unquoted, the collations and names are quoted
+func (b *Blueprint) validate() error {
+	var (
+		algorithms = []string{"btree", "hash", "gist", "gin", "brin", "spgist", "rtree"}
+		actions    = []string{ForeignActionCascade, ForeignActionSetNull, ForeignActionSetDefault, ForeignActionRestrict, ForeignActionNoAction}
+		invalid    = func(name string, value interface{}) error {
+			return fmt.Errorf("schema err: invalid %s %q of %s", name, value, b.GetTable())
+		}
+	)
+
+	if b.Engine != "" && !keywordPattern.MatchString(b.Engine) {
+		return invalid("engine", b.Engine)
+	}
+	if b.Charset != "" && !keywordPattern.MatchString(b.Charset) {
+		return invalid("charset", b.Charset)
+	}
 
-	var statements []string
+	for _, column := range b.columns {
+		if charset, ok := column.Attributes[ColumnAttrCharset].(string); ok && !keywordPattern.MatchString(charset) {
+			return invalid("charset", charset)
+		}
+	}
 
 	for _, command := range b.commands {
-		statements = append(statements, grammar.Compile(b, command))
+		switch command.Name {
+		case commandPrimary, commandUnique, commandIndex, commandFulltext, commandSpatialIndex:
+			if columns, _ := command.Attributes[commandAttrColumns].([]string); len(columns) == 0 || inArray("", columns) {
+				return fmt.Errorf("schema err: the %s %s of %s has no columns", command.Name, command.Attributes[commandAttrIndex], b.GetTable())
+			}
+		case commandForeign:
+			if on, _ := command.Attributes[commandAttrOn].(string); on == "" {
+				return fmt.Errorf("schema err: the foreign key %s of %s has no referenced table, use On to set it",
+					command.Attributes[commandAttrIndex], b.GetTable())
+			}
+		}
+		if algorithm, _ := command.Attributes[commandAttrAlgorithm].(string); algorithm != "" && !inArray(strings.ToLower(algorithm), algorithms) {
+			return invalid("index algorithm", algorithm)
+		}
+		if parser, _ := command.Attributes[commandAttrParser].(string); parser != "" && !keywordPattern.MatchString(parser) {
+			return invalid("fulltext parser", parser)
+		}
+		for _, key := range []string{commandAttrOnDelete, commandAttrOnUpdate} {
+			if action, ok := command.Attributes[key].(string); ok && action != "" && !inArray(strings.ToLower(action), actions) {
+				return invalid("foreign key action", action)
+			}
+		}
+	}
+
+	return nil
+}
+
+// destructive check the command may lose data: drop table, drop column, or change a column to a narrower type
+func (b *Blueprint) destructive(command *Command) bool {
+	switch command.Name {
+	case commandDrop, commandDropIfExists, commandDropColumn:
+		return true
+	case commandChange:
+		for _, column := range b.getChangedColumns() {
+			if current := b.currentColumn(column.Name); current != nil && column.narrows(current.toColumn()) {
+				return true
+			}
+		}
 	}
+	return false
+}
 
-	statements = unique(statements)
+// commandColumns get the columns affected by the command
+func (b *Blueprint) commandColumns(command *Command) []string {
+	var columns []*Column
 
-	return statements
+	switch command.Name {
+	case commandCreate, commandAdd:
+		columns = b.getAddedColumns()
+	case commandChange:
+		columns = b.getChangedColumns()
+	case commandRenameColumn:
+		return []string{command.Attributes[commandAttrFrom].(string), command.Attributes[commandAttrTo].(string)}
+	default:
+		return toStrings(command.Attributes[commandAttrColumns])
+	}
+
+	var names []string
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	return names
 }
 
 // Create Indicate that the table needs to be created.
@@ -407,16 +852,17 @@ func (b *Blueprint) dropIfExists() *Command {
 
 // addImpliedCommands Add the commands that are implied by the blueprint's state.
 func (b *Blueprint) addImpliedCommands() {
-	if !b.creating() && len(b.getAddedColumns()) > 0 {
+	if !b.creating() && len(b.getAddedColumns()) > 0 && !b.hasCommand(commandAdd) {
 		// b.commands = append([]*Command{b.createCommand(commandAdd)}, b.commands...)
 		b.addCommand(commandAdd)
 	}
 
-	if !b.creating() && len(b.getChangedColumns()) > 0 {
+	if !b.creating() && len(b.getChangedColumns()) > 0 && !b.hasCommand(commandChange) {
 		b.addCommand(commandChange)
 	}
 
 	b.addFluentIndexes()
+	b.addFluentChecks()
 }
 
 // addFluentIndexes Add the index commands fluently specified on columns.
@@ -429,7 +875,7 @@ func (b *Blueprint) addFluentIndexes() {
 			if continue2 {
 				continue
 			}
-			if _, ok := column.Attributes[index]; ok {
+			if column.Attributes[index] == true {
 				switch index {
 				case commandPrimary:
 					b.Primary(column.Name)
@@ -442,13 +888,37 @@ func (b *Blueprint) addFluentIndexes() {
 				continue2 = true
 			}
 		}
+
+		if options, ok := column.Attributes[ColumnAttrFulltext].([]IndexOption); ok {
+			b.Fulltext(column.Name, options...)
+			delete(column.Attributes, ColumnAttrFulltext)
+		}
+	}
+}
+
+// addFluentChecks Add the check constraints fluently specified on columns.
+func (b *Blueprint) addFluentChecks() {
+	for _, column := range b.columns {
+		if expression, ok := column.Attributes[ColumnAttrCheck].(string); ok {
+			b.addCommand(commandCheck, Map{
+				commandAttrIndex:      b.createIndexName(commandCheck, []string{column.Name}),
+				commandAttrExpression: expression,
+				commandAttrColumns:    []string{column.Name},
+			})
+			delete(column.Attributes, ColumnAttrCheck)
+		}
 	}
 }
 
 // creating check has create command
 func (b *Blueprint) creating() bool {
+	return b.hasCommand(commandCreate)
+}
+
+// hasCommand check has the given command
+func (b *Blueprint) hasCommand(name string) bool {
 	for _, command := range b.commands {
-		if command.Name == commandCreate {
+		if command.Name == name {
 			return true
 		}
 	}
@@ -473,22 +943,41 @@ func (b *Blueprint) createCommand(name string, parameters ...Map) (command *Comm
 }
 
 // indexCommand add index command
-func (b *Blueprint) indexCommand(t string, columns interface{}, algorithm ...string) {
-	if column, ok := columns.(string); ok {
-		b.addCommand(t, Map{
-			commandAttrIndex:     b.createIndexName(t, []string{column}),
-			commandAttrAlgorithm: varDef(algorithm, ""),
-			commandAttrColumns:   []string{column},
-		})
-	}
-
-	if column, ok := columns.([]string); ok {
-		b.addCommand(t, Map{
+func (b *Blueprint) indexCommand(t string, columns interface{}, algorithm ...string) *Command {
+	if column := toStrings(columns); column != nil {
+		return b.addCommand(t, Map{
 			commandAttrIndex:     b.createIndexName(t, column),
 			commandAttrAlgorithm: varDef(algorithm, ""),
 			commandAttrColumns:   column,
 		})
 	}
+	return nil
+}
+
+// IndexOption an option of the index command
+type IndexOption func(command *Command)
+
+// WithParser use the parser of the fulltext index, e.g. ngram for the chinese, japanese and korean, mysql only
+func WithParser(parser string) IndexOption {
+	return func(command *Command) {
+		command.Attributes[commandAttrParser] = parser
+	}
+}
+
+// dropIndexCommand add drop index command, index is the index name string,
+// or the columns []string to generate the index name of type t
+func (b *Blueprint) dropIndexCommand(command string, t string, index interface{}) *Command {
+	params := Map{}
+
+	switch value := index.(type) {
+	case string:
+		params[commandAttrIndex] = value
+	case []string:
+		params[commandAttrIndex] = b.createIndexName(t, value)
+		params[commandAttrColumns] = value
+	}
+
+	return b.addCommand(command, params)
 }
 
 // createIndexName create index name
@@ -496,3 +985,18 @@ func (b *Blueprint) createIndexName(t string, columns []string) string {
 	index := strings.ToLower(b.config.Prefix + b.table + "_" + strings.Join(columns, "_") + "_" + t)
 	return replaceByArray(index, []string{"-", "_", ".", "_"})
 }
+
+// modelTable get the table name of a model, the TableName method of the model,
+// or the plural snake case of the struct name
+func modelTable(model interface{}) string {
+	if tabler, ok := model.(interface{ TableName() string }); ok {
+		return tabler.TableName()
+	}
+
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return plural(snake(t.Name()))
+}
@@ -3,15 +3,27 @@ package schema
 import (
 	"context"
 	"testing"
+	"time"
 )
 
 func TestBlueprint_ToSql(t *testing.T) {
 	type sqlCase struct {
 		name     string
 		table    string
+		version  string
+		current  []*ColumnInfo
 		sql      []string
 		callback func(table *Blueprint)
 	}
+
+	var (
+		def     = "a"
+		current = []*ColumnInfo{
+			{Name: "id", Type: "bigint(20) unsigned", AutoIncrement: true},
+			{Name: "name", Type: "varchar(50)", Nullable: true, Default: &def, Charset: "utf8mb4", Collation: "utf8mb4_bin", Comment: "姓名"},
+		}
+	)
+
 	cases := []sqlCase{
 		{
 			name:  "set engine charset collation",
@@ -182,7 +194,7 @@ func TestBlueprint_ToSql(t *testing.T) {
 			name:  "Json",
 			table: "users",
 			sql: []string{
-				"create table `users` (`data` json not null, `binary` binary not null, `blob` binary not null) default character set utf8mb4 collate 'utf8mb4_unicode_ci' engine = InnoDB",
+				"create table `users` (`data` json not null, `binary` binary not null, `blob` blob not null) default character set utf8mb4 collate 'utf8mb4_unicode_ci' engine = InnoDB",
 			},
 			callback: func(table *Blueprint) {
 				table.create()
@@ -248,9 +260,9 @@ func TestBlueprint_ToSql(t *testing.T) {
 			table: "users",
 			sql: []string{
 				"create table `users` (`id` int unsigned not null, `account` varchar(50) not null, `name` varchar(30) not null, `age` int not null) default character set utf8mb4 collate 'utf8mb4_unicode_ci' engine = InnoDB",
-				"alter table `users` add unique users_account_name_unique(`account`, `name`)",
-				"alter table `users` add primary key users_id_primary(`id`)",
-				"alter table `users` add index users_age_index(`age`)",
+				"alter table `users` add unique `users_account_name_unique`(`account`, `name`)",
+				"alter table `users` add primary key `users_id_primary`(`id`)",
+				"alter table `users` add index `users_age_index`(`age`)",
 			},
 			callback: func(table *Blueprint) {
 				table.create()
@@ -262,6 +274,326 @@ func TestBlueprint_ToSql(t *testing.T) {
 				table.Unique([]string{"account", "name"})
 			},
 		},
+		{
+			name:  "Foreign",
+			table: "posts",
+			sql: []string{
+				"create table `posts` (`id` bigint unsigned not null auto_increment primary key, `user_id` bigint unsigned not null, `author_id` bigint unsigned null) default character set utf8mb4 collate 'utf8mb4_unicode_ci' engine = InnoDB",
+				"alter table `posts` add constraint `posts_user_id_foreign` foreign key (`user_id`) references `users` (`id`) on delete cascade on update cascade",
+				"alter table `posts` add constraint `fk_author` foreign key (`author_id`) references `users` (`uid`) on delete set null",
+			},
+			callback: func(table *Blueprint) {
+				table.create()
+				table.Id()
+				table.UnsignedBigInt("user_id")
+				table.UnsignedBigInt("author_id").Nullable()
+				table.Foreign("user_id").References("id").On("users").CascadeOnDelete().CascadeOnUpdate()
+				table.Foreign("author_id").References("uid").On("users").NullOnDelete().Name("fk_author")
+			},
+		},
+		{
+			name:  "DropForeign",
+			table: "posts",
+			sql: []string{
+				"alter table `posts` drop foreign key `posts_user_id_foreign`",
+				"alter table `posts` drop foreign key `fk_author`",
+			},
+			callback: func(table *Blueprint) {
+				table.DropForeign([]string{"user_id"})
+				table.DropForeign("fk_author")
+			},
+		},
+		{
+			name:  "ForeignId",
+			table: "posts",
+			sql: []string{
+				"create table `posts` (`user_id` bigint unsigned not null, `category_id` bigint unsigned null, `author_uuid` char(36) not null, `team_member_id` bigint unsigned not null) default character set utf8mb4 collate 'utf8mb4_unicode_ci' engine = InnoDB",
+				"alter table `posts` add constraint `posts_user_id_foreign` foreign key (`user_id`) references `users` (`id`) on delete cascade",
+				"alter table `posts` add constraint `posts_category_id_foreign` foreign key (`category_id`) references `categories` (`id`) on delete set null",
+				"alter table `posts` add constraint `posts_author_uuid_foreign` foreign key (`author_uuid`) references `authors` (`uuid`)",
+				"alter table `posts` add constraint `posts_team_member_id_foreign` foreign key (`team_member_id`) references `team_members` (`id`)",
+			},
+			callback: func(table *Blueprint) {
+				type TeamMember struct {
+					ID uint64
+				}
+
+				table.create()
+				table.ForeignId("user_id").Constrained().CascadeOnDelete()
+				table.ForeignId("category_id").Nullable().Constrained().NullOnDelete()
+				table.ForeignUuid("author_uuid").Constrained()
+				table.ForeignIdFor(&TeamMember{}).Constrained()
+			},
+		},
+		{
+			name:  "Drop_Indexes",
+			table: "users",
+			sql: []string{
+				"alter table `users` drop primary key",
+				"alter table `users` drop index `users_account_unique`",
+				"alter table `users` drop index `users_age_index`",
+				"alter table `users` drop index `users_bio_fulltext`",
+				"alter table `users` drop index `users_location_spatialindex`",
+				"alter table `users` rename index `users_name_index` to `users_nickname_index`",
+			},
+			callback: func(table *Blueprint) {
+				table.DropPrimary()
+				table.DropUnique([]string{"account"})
+				table.DropIndex("users_age_index")
+				table.DropFulltext([]string{"bio"})
+				table.DropSpatialIndex([]string{"location"})
+				table.RenameIndex("users_name_index", "users_nickname_index")
+			},
+		},
+		{
+			name:    "Change_Merge",
+			table:   "users",
+			current: current,
+			sql: []string{
+				"alter table `users` modify `name` varchar(100) character set utf8mb4 collate 'utf8mb4_bin' null default 'a' comment '姓名', modify `id` bigint unsigned not null auto_increment",
+			},
+			callback: func(table *Blueprint) {
+				table.String("name", 100).Change()
+				table.BigIncrements("id").Change()
+			},
+		},
+		{
+			name:    "Change_Merge_Given",
+			table:   "users",
+			current: current,
+			sql: []string{
+				"alter table `users` modify `name` text character set utf8mb4 collate 'utf8mb4_general_ci' not null",
+			},
+			callback: func(table *Blueprint) {
+				table.Text("name").Nullable(false).Default(nil).Comment("").Collation("utf8mb4_general_ci").Change()
+			},
+		},
+		{
+			name:  "RenameColumn",
+			table: "users",
+			sql: []string{
+				"alter table `users` rename column `name` to `nickname`",
+			},
+			callback: func(table *Blueprint) {
+				table.RenameColumn("name", "nickname")
+			},
+		},
+		{
+			name:    "RenameColumn_MySQL8",
+			table:   "users",
+			version: "8.0.32",
+			sql: []string{
+				"alter table `users` rename column `name` to `nickname`",
+			},
+			callback: func(table *Blueprint) {
+				table.RenameColumn("name", "nickname")
+			},
+		},
+		{
+			name:    "RenameColumn_MySQL57",
+			table:   "users",
+			version: "5.7.41-log",
+			current: current,
+			sql: []string{
+				"alter table `users` change `name` `nickname` varchar(50) character set utf8mb4 collate 'utf8mb4_bin' null default 'a' comment '姓名'",
+				"alter table `users` change `id` `user_id` bigint(20) unsigned not null auto_increment",
+			},
+			callback: func(table *Blueprint) {
+				table.RenameColumn("name", "nickname")
+				table.RenameColumn("id", "user_id")
+			},
+		},
+		{
+			name:    "RenameColumn_MySQL57_Types",
+			table:   "users",
+			version: "5.7.41-log",
+			current: []*ColumnInfo{
+				{Name: "score", Type: "double"},
+				{Name: "avatar", Type: "longblob", Nullable: true},
+				{Name: "status", Type: "enum('Draft','Published')"},
+			},
+			sql: []string{
+				"alter table `users` change `score` `rating` double not null",
+				"alter table `users` change `avatar` `photo` longblob null",
+				"alter table `users` change `status` `state` enum('Draft','Published') not null",
+			},
+			callback: func(table *Blueprint) {
+				table.RenameColumn("score", "rating")
+				table.RenameColumn("avatar", "photo")
+				table.RenameColumn("status", "state")
+			},
+		},
+		{
+			name:    "RenameColumn_MariaDB",
+			table:   "users",
+			version: "10.6.12-MariaDB-1:10.6.12+maria~ubu2004",
+			current: current,
+			sql: []string{
+				"alter table `users` change `name` `nickname` varchar(50) character set utf8mb4 collate 'utf8mb4_bin' null default 'a' comment '姓名'",
+			},
+			callback: func(table *Blueprint) {
+				table.RenameColumn("name", "nickname")
+			},
+		},
+		{
+			name:  "Default_Types",
+			table: "users",
+			sql: []string{
+				"alter table `users` add `active` tinyint(1) not null default 1, add `flag` tinyint(1) not null default 0, add `age` int not null default 18, add `score` int not null default 0, add `price` decimal(8, 2) not null default 9.5, add `code` varchar(255) not null default '18', add `birthday` date not null default '2024-02-01', add `created_at` timestamp not null default CURRENT_TIMESTAMP, add `data` json not null default (json_array())",
+			},
+			callback: func(table *Blueprint) {
+				table.Boolean("active").Default(true)
+				table.Boolean("flag").Default("false")
+				table.Int("age").Default(18)
+				table.Int("score").Default("0")
+				table.Decimal("price", 8, 2).Default(9.5)
+				table.String("code").Default(18)
+				table.Date("birthday").Default(time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC))
+				table.Timestamp("created_at").Default(Raw("CURRENT_TIMESTAMP"))
+				table.Json("data").Default(Raw("(json_array())"))
+			},
+		},
+		{
+			name:  "UseCurrent_Precision",
+			table: "users",
+			sql: []string{
+				"alter table `users` add `logged_at` datetime(3) not null default CURRENT_TIMESTAMP(3), add `seen` time(6) null, add `published_at` timestamp null default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP, add `created_at` timestamp(6) null, add `updated_at` timestamp(6) null, add `deleted_at` timestamp(6) null",
+			},
+			callback: func(table *Blueprint) {
+				table.DateTime("logged_at", 3).UseCurrent()
+				table.Time("seen", 6).Nullable()
+				table.Timestamp("published_at").Nullable().UseCurrent().UseCurrentOnUpdate()
+				table.Timestamps(6)
+				table.SoftDeletes(6)
+			},
+		},
+		{
+			name:    "Generated",
+			table:   "users",
+			current: []*ColumnInfo{{Name: "full_name", Type: "varchar(100)", Nullable: true, Comment: "姓名", Generated: "virtual", Expression: "concat(`first_name`,' ',`last_name`)"}},
+			sql: []string{
+				"alter table `users` add `payload` json not null, add `name` varchar(50) generated always as (json_unquote(json_extract(`payload`, '$.name'))) virtual null comment '名称', add `age` int unsigned generated always as (`payload`->>'$.age') stored not null",
+				"alter table `users` modify `full_name` varchar(200) generated always as (concat(`first_name`,' ',`last_name`)) virtual null comment '姓名'",
+			},
+			callback: func(table *Blueprint) {
+				table.Json("payload")
+				table.String("name", 50).VirtualAs("json_unquote(json_extract(`payload`, '$.name'))").Nullable().Default("a").Comment("名称")
+				table.UnsignedInt("age").StoredAs("`payload`->>'$.age'")
+				table.String("full_name", 200).Change()
+			},
+		},
+		{
+			name:  "After_First",
+			table: "users",
+			sql: []string{
+				"alter table `users` add `uuid` char(36) not null first, add `first_name` varchar(50) not null after `id`, add `last_name` varchar(50) not null after `first_name`, add `email` varchar(100) null after `last_name`",
+			},
+			callback: func(table *Blueprint) {
+				table.Uuid("uuid").First()
+				table.After("id", func(table *Blueprint) {
+					table.String("first_name", 50)
+					table.String("last_name", 50)
+					table.String("email", 100).Nullable()
+				})
+			},
+		},
+		{
+			name:  "ReorderColumns",
+			table: "users",
+			current: []*ColumnInfo{
+				{Name: "name", Type: "varchar(50)", Nullable: true, Default: &def, Charset: "utf8mb4", Collation: "utf8mb4_bin", Comment: "姓名"},
+				{Name: "age", Type: "int(11)"},
+				{Name: "email", Type: "varchar(100)"},
+				{Name: "id", Type: "bigint(20) unsigned", AutoIncrement: true},
+				{Name: "score", Type: "double"},
+				{Name: "avatar", Type: "blob", Nullable: true},
+			},
+			sql: []string{
+				"alter table `users` modify `id` bigint(20) unsigned not null auto_increment first",
+				"alter table `users` modify `email` varchar(100) not null after `name`",
+				"alter table `users` modify `avatar` blob null after `email`",
+				"alter table `users` modify `score` double not null after `avatar`",
+			},
+			callback: func(table *Blueprint) {
+				table.ReorderColumns("id", "name", "email", "avatar", "score")
+			},
+		},
+		{
+			name:  "Fulltext",
+			table: "posts",
+			sql: []string{
+				"alter table `posts` drop index `posts_summary_fulltext`",
+				"alter table `posts` add fulltext index `posts_title_summary_fulltext`(`title`, `summary`) with parser ngram",
+				"alter table `posts` add `body` text not null",
+				"alter table `posts` add fulltext index `posts_body_fulltext`(`body`)",
+			},
+			callback: func(table *Blueprint) {
+				table.DropFulltext([]string{"summary"})
+				table.Text("body").Fulltext()
+				table.Fulltext([]string{"title", "summary"}, WithParser("ngram"))
+			},
+		},
+		{
+			name:  "Spatial",
+			table: "stores",
+			sql: []string{
+				"alter table `stores` drop index `stores_area_spatialindex`",
+				"alter table `stores` add spatial index `stores_position_spatialindex`(`position`)",
+				"alter table `stores` add `location` point srid 4326 not null, add `shape` geometry null, add `route` linestring srid 0 not null, add `area` polygon not null, add `branches` multipoint not null, add `zones` multipolygon not null, add `features` geometrycollection not null",
+			},
+			callback: func(table *Blueprint) {
+				table.DropSpatialIndex([]string{"area"})
+				table.SpatialIndex("position")
+				table.Point("location", 4326)
+				table.Geometry("shape").Nullable()
+				table.LineString("route", 0)
+				table.Polygon("area")
+				table.MultiPoint("branches")
+				table.MultiPolygon("zones")
+				table.GeometryCollection("features")
+			},
+		},
+		{
+			name:  "Create_Check",
+			table: "users",
+			sql: []string{
+				"create table `users` (`age` int not null, `start_at` date not null, `end_at` date not null, constraint `users_period_check` check (`end_at` > `start_at`), constraint `users_age_check` check (`age` >= 18)) default character set utf8mb4 collate 'utf8mb4_unicode_ci' engine = InnoDB",
+			},
+			callback: func(table *Blueprint) {
+				table.create()
+				table.Int("age").Check("`age` >= 18")
+				table.Date("start_at")
+				table.Date("end_at")
+				table.Check("users_period_check", "`end_at` > `start_at`")
+			},
+		},
+		{
+			name:  "Check_DropCheck",
+			table: "users",
+			sql: []string{
+				"alter table `users` drop check `users_age_check`",
+				"alter table `users` add `score` int not null",
+				"alter table `users` add constraint `users_score_check` check (`score` between 0 and 100)",
+			},
+			callback: func(table *Blueprint) {
+				table.DropCheck("users_age_check")
+				table.Int("score").Check("`score` between 0 and 100")
+			},
+		},
+		{
+			name:  "Escape",
+			table: "user`s",
+			sql: []string{
+				"alter table `user``s` comment = 'it''s \\\\'' or 1'",
+				"alter table `user``s` add `na``me` varchar(20) not null default 'it''s' comment 'user''s name', add `type` enum('a''b', 'c\\\\d') not null default 'c:\\\\tmp'",
+				"alter table `user``s` add index `user``s_na``me_index`(`na``me`)",
+			},
+			callback: func(table *Blueprint) {
+				table.String("na`me", 20).Default("it's").Comment("user's name").Index()
+				table.Enum("type", []string{"a'b", `c\d`}).Default(`c:\tmp`)
+				table.Comment(`it's \' or 1`)
+			},
+		},
 	}
 
 	newSchema := NewSchema(context.Background(), &Config{
@@ -273,7 +605,12 @@ func TestBlueprint_ToSql(t *testing.T) {
 
 	for _, item := range cases {
 		blueprint := NewBlueprint(newSchema, item.table, item.callback)
-		sql := blueprint.ToSql(localGrammar)
+		blueprint.version = item.version
+		blueprint.currentColumns = item.current
+		sql, err := blueprint.ToSql(localGrammar)
+		if err != nil {
+			t.Fatal("ToSql err:", item.name, err)
+		}
 		if len(sql) != len(item.sql) {
 			t.Fatal("ToSql err:", item.name, "\nsql:", item.sql, "\ngen:", sql)
 		}
@@ -284,3 +621,70 @@ func TestBlueprint_ToSql(t *testing.T) {
 		}
 	}
 }
+
+func TestMysqlGrammar_currentColumns(t *testing.T) {
+	newSchema := NewSchema(context.Background(), &Config{})
+
+	blueprint := NewBlueprint(newSchema, "users", func(table *Blueprint) {
+		table.RenameColumn("name", "nickname")
+	})
+	blueprint.version = "5.7.41-log"
+	if _, err := blueprint.ToSql(localGrammar); err == nil || err.Error() != "schema err: column users.name not found" {
+		t.Fatal("RenameColumn err:", err)
+	}
+
+	// the reordered columns are moved by the current definitions
+	sql, err := newSchema.Pretend(func(s *Schema) error {
+		return s.Table("users", func(table *Blueprint) {
+			table.ReorderColumns("id", "name")
+		})
+	})
+	if err == nil || err.Error() != "schema err: reorder the columns of users requires the current columns, config.DB is nil" || len(sql) != 0 {
+		t.Fatal("ReorderColumns err:", err, sql)
+	}
+
+	sqlite := NewSchema(context.Background(), &Config{Driver: DriverSqlite})
+	blueprint = NewBlueprint(sqlite, "users", func(table *Blueprint) {
+		table.ReorderColumns("id", "name")
+	})
+	if _, err = blueprint.ToSql(sqlite.GetGrammar()); err == nil || err.Error() != "schema err: sqlite does not support reorderColumns command of users" {
+		t.Fatal("ReorderColumns err:", err)
+	}
+}
+
+func TestBlueprint_validate(t *testing.T) {
+	type validateCase struct {
+		name     string
+		callback func(table *Blueprint)
+		err      string
+	}
+
+	cases := []validateCase{
+		{"Engine", func(table *Blueprint) { table.create(); table.Engine = "InnoDB; drop table users" }, `schema err: invalid engine "InnoDB; drop table users" of users`},
+		{"Charset", func(table *Blueprint) { table.create(); table.Charset = "utf8mb4 collate x" }, `schema err: invalid charset "utf8mb4 collate x" of users`},
+		{"Column_Charset", func(table *Blueprint) { table.String("name").Charset("latin1,") }, `schema err: invalid charset "latin1," of users`},
+		{"Algorithm", func(table *Blueprint) { table.Index("name", "btree(name)") }, `schema err: invalid index algorithm "btree(name)" of users`},
+		{"Parser", func(table *Blueprint) { table.Fulltext("body", WithParser("ngram)")) }, `schema err: invalid fulltext parser "ngram)" of users`},
+		{"OnDelete", func(table *Blueprint) { table.Foreign("team_id").On("teams").OnDelete("cascade, drop") }, `schema err: invalid foreign key action "cascade, drop" of users`},
+		{"Index_Columns", func(table *Blueprint) { table.Index([]string{}) }, "schema err: the index users__index of users has no columns"},
+		{"Unique_Columns", func(table *Blueprint) { table.Unique("").Name("users_unique") }, "schema err: the unique users_unique of users has no columns"},
+		{"Primary_Columns", func(table *Blueprint) { table.Primary([]string{}) }, "schema err: the primary users__primary of users has no columns"},
+		{"Foreign_On", func(table *Blueprint) { table.Foreign("user_id") }, "schema err: the foreign key users_user_id_foreign of users has no referenced table, use On to set it"},
+		{"Valid", func(table *Blueprint) {
+			table.create()
+			table.String("name").Charset("latin1").Collation("latin1 swedish")
+			table.Index("name", "BTREE")
+			table.Fulltext("body", WithParser("ngram"))
+			table.Foreign("team_id").On("teams").OnDelete("SET NULL").OnUpdate(ForeignActionSetDefault)
+		}, ""},
+	}
+
+	newSchema := NewSchema(context.Background(), &Config{})
+
+	for _, item := range cases {
+		_, err := NewBlueprint(newSchema, "users", item.callback).ToSql(localGrammar)
+		if (err == nil && item.err != "") || (err != nil && err.Error() != item.err) {
+			t.Fatal("validate err:", item.name, err)
+		}
+	}
+}
@@ -1,9 +1,25 @@
 package schema
 
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expression a raw sql expression which is not quoted, e.g. a default value CURRENT_TIMESTAMP
+type Expression string
+
+// Raw create a raw sql expression
+func Raw(value string) Expression {
+	return Expression(value)
+}
+
 type Column struct {
 	Type       string
 	Name       string
 	Attributes Map
+
+	blueprint *Blueprint
 }
 
 // Comment add column comment
@@ -12,12 +28,60 @@ func (c *Column) Comment(comment string) *Column {
 	return c
 }
 
-// Default add column default value
+// Default add column default value, nil drops the default value of a changed column.
+// The value is rendered by the column type: bools are 1 or 0, numbers are not quoted, times are formatted
+// by the date and time type, and schema.Raw expressions are not quoted.
 func (c *Column) Default(value interface{}) *Column {
 	c.Attributes[ColumnAttrDefault] = value
 	return c
 }
 
+// UseCurrent set the default value of the timestamp column to CURRENT_TIMESTAMP
+func (c *Column) UseCurrent() *Column {
+	c.Attributes[ColumnAttrUseCurrent] = true
+	return c
+}
+
+// UseCurrentOnUpdate set the timestamp column to CURRENT_TIMESTAMP when the row is updated, mysql only
+func (c *Column) UseCurrentOnUpdate() *Column {
+	c.Attributes[ColumnAttrUseCurrentOnUpdate] = true
+	return c
+}
+
+// VirtualAs create a virtual generated column by the expression, e.g. "json_unquote(json_extract(`payload`, '$.name'))"
+func (c *Column) VirtualAs(expression string) *Column {
+	delete(c.Attributes, ColumnAttrStoredAs)
+	c.Attributes[ColumnAttrVirtualAs] = expression
+	return c
+}
+
+// StoredAs create a stored generated column by the expression
+func (c *Column) StoredAs(expression string) *Column {
+	delete(c.Attributes, ColumnAttrVirtualAs)
+	c.Attributes[ColumnAttrStoredAs] = expression
+	return c
+}
+
+// After place the column after the given column, mysql only
+func (c *Column) After(column string) *Column {
+	delete(c.Attributes, ColumnAttrFirst)
+	c.Attributes[ColumnAttrAfter] = column
+	return c
+}
+
+// First place the column at the beginning of the table, mysql only
+func (c *Column) First() *Column {
+	delete(c.Attributes, ColumnAttrAfter)
+	c.Attributes[ColumnAttrFirst] = true
+	return c
+}
+
+// Check add a check constraint of the column named by the table and the column, e.g. users_age_check
+func (c *Column) Check(expression string) *Column {
+	c.Attributes[ColumnAttrCheck] = expression
+	return c
+}
+
 // Nullable Can it be empty, default to true
 func (c *Column) Nullable(value ...bool) *Column {
 	c.Attributes[ColumnAttrNullable] = varDef(value, true)
@@ -42,7 +106,14 @@ func (c *Column) Index() *Column {
 	return c
 }
 
-// Change column
+// Fulltext add fulltext index, e.g. Fulltext(schema.WithParser("ngram"))
+func (c *Column) Fulltext(options ...IndexOption) *Column {
+	c.Attributes[ColumnAttrFulltext] = options
+	return c
+}
+
+// Change column, the nullable, default, comment, charset and collation
+// of the current column are kept unless they are given
 func (c *Column) Change() *Column {
 	c.Attributes[ColumnAttrChange] = true
 	return c
@@ -59,3 +130,229 @@ func (c *Column) Collation(collation string) *Column {
 	c.Attributes[ColumnAttrCollate] = collation
 	return c
 }
+
+// Constrained add a foreign key constraint on the column, the referenced table and column
+// are inferred from the column name, e.g. user_id references users.id,
+// or can be given by tableAndColumn, the column default id
+func (c *Column) Constrained(tableAndColumn ...string) *ForeignKeyDefinition {
+	table, column := c.foreignTable()
+	if len(tableAndColumn) > 0 {
+		table = tableAndColumn[0]
+		column = "id"
+	}
+	if len(tableAndColumn) > 1 {
+		column = tableAndColumn[1]
+	}
+
+	return c.blueprint.Foreign(c.Name).References(column).On(table)
+}
+
+// References add a foreign key constraint on the column referencing the given column,
+// the table should be set by On
+func (c *Column) References(column string) *ForeignKeyDefinition {
+	return c.blueprint.Foreign(c.Name).References(column)
+}
+
+// foreignTable infer the referenced table and column from the column name
+func (c *Column) foreignTable() (table string, column string) {
+	if on, ok := c.Attributes[ColumnAttrOn].(string); ok {
+		return on, "id"
+	}
+
+	if i := strings.LastIndex(c.Name, "_"); i > 0 && i < len(c.Name)-1 {
+		return plural(c.Name[:i]), c.Name[i+1:]
+	}
+
+	return plural(c.Name), "id"
+}
+
+// merge Merge the attributes of the current column which are not given into the changed column
+func (c *Column) merge(current *ColumnInfo) {
+	var (
+		attrs = current.toColumn().Attributes
+		keys  = []string{ColumnAttrNullable, ColumnAttrDefault, ColumnAttrComment}
+	)
+
+	// the charset and collation of a string column
+	if inArray(c.Type, []string{
+		ColumnTypeChar, ColumnTypeVarchar, ColumnTypeTinyText, ColumnTypeText, ColumnTypeMediumText,
+		ColumnTypeLongText, ColumnTypeEnum, ColumnTypeSet,
+	}) {
+		keys = append(keys, ColumnAttrCharset, ColumnAttrCollate)
+	}
+
+	// the current timestamp default is kept when the default is not given
+	if _, given := c.Attributes[ColumnAttrDefault]; !given {
+		keys = append(keys, ColumnAttrUseCurrent)
+	}
+	keys = append(keys, ColumnAttrUseCurrentOnUpdate)
+
+	// the generated expression is kept when the column is not given as generated
+	if _, storage := c.generated(); storage == "" {
+		keys = append(keys, ColumnAttrVirtualAs, ColumnAttrStoredAs)
+	}
+
+	for _, key := range keys {
+		if value, ok := attrs[key]; ok {
+			if _, given := c.Attributes[key]; !given {
+				c.Attributes[key] = value
+			}
+		}
+	}
+
+	if def, ok := c.Attributes[ColumnAttrDefault]; ok && def == nil {
+		delete(c.Attributes, ColumnAttrDefault)
+	}
+}
+
+// narrows check changing the current column to the column may lose data:
+// a smaller integer or string, fewer decimal digits, removed enum values or another type family
+func (c *Column) narrows(current *Column) bool {
+	var (
+		integers = []string{ColumnTypeBoolean, ColumnTypeTinyInt, ColumnTypeSmallInt, ColumnTypeMediumInt, ColumnTypeInt, ColumnTypeBigInt}
+		texts    = []string{ColumnTypeChar, ColumnTypeVarchar, ColumnTypeTinyText, ColumnTypeText, ColumnTypeMediumText, ColumnTypeLongText}
+		rank     = func(types []string, t string) int {
+			for i, item := range types {
+				if item == t {
+					return i
+				}
+			}
+			return -1
+		}
+		number = func(column *Column, key string) int {
+			n, _ := column.Attributes[key].(int)
+			return n
+		}
+		capacity = func(column *Column) int {
+			switch column.Type {
+			case ColumnTypeChar, ColumnTypeVarchar:
+				return number(column, ColumnAttrLength)
+			case ColumnTypeTinyText:
+				return 1<<8 - 1
+			case ColumnTypeText:
+				return 1<<16 - 1
+			case ColumnTypeMediumText:
+				return 1<<24 - 1
+			}
+			return 1<<32 - 1
+		}
+	)
+
+	switch {
+	case rank(integers, c.Type) >= 0 && rank(integers, current.Type) >= 0:
+		return rank(integers, c.Type) < rank(integers, current.Type) ||
+			(c.Attributes[ColumnAttrUnsigned] == true) != (current.Attributes[ColumnAttrUnsigned] == true)
+	case rank(texts, c.Type) >= 0 && rank(texts, current.Type) >= 0:
+		return capacity(c) < capacity(current)
+	case c.Type != current.Type:
+		return true
+	}
+
+	switch c.Type {
+	case ColumnTypeFloat, ColumnTypeDouble, ColumnTypeDecimal:
+		return number(c, ColumnAttrTotal) < number(current, ColumnAttrTotal) ||
+			number(c, ColumnAttrPlaces) < number(current, ColumnAttrPlaces) ||
+			(c.Attributes[ColumnAttrUnsigned] == true && current.Attributes[ColumnAttrUnsigned] != true)
+	case ColumnTypeEnum, ColumnTypeSet:
+		allowed, _ := c.Attributes[ColumnAttrAllowed].([]string)
+		values, _ := current.Attributes[ColumnAttrAllowed].([]string)
+		for _, value := range values {
+			if !inArray(value, allowed) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// numericTypes the column types whose default values are numbers
+var numericTypes = []string{
+	ColumnTypeBoolean, ColumnTypeTinyInt, ColumnTypeSmallInt, ColumnTypeMediumInt, ColumnTypeInt, ColumnTypeBigInt,
+	ColumnTypeFloat, ColumnTypeDouble, ColumnTypeDecimal,
+}
+
+// spatialTypes the spatial column types
+var spatialTypes = []string{
+	ColumnTypeGeometry, ColumnTypePoint, ColumnTypeLineString, ColumnTypePolygon,
+	ColumnTypeMultiPoint, ColumnTypeMultiPolygon, ColumnTypeGeometryCollection,
+}
+
+// defaultValue get the default value of the column, raw is true if the value is not quoted
+func (c *Column) defaultValue() (value string, raw bool) {
+	switch def := c.Attributes[ColumnAttrDefault].(type) {
+	case Expression:
+		return string(def), true
+	case bool:
+		return ternary(def, "1", "0"), true
+	case time.Time:
+		switch c.Type {
+		case ColumnTypeDate:
+			return def.Format("2006-01-02"), false
+		case ColumnTypeTime:
+			return def.Format("15:04:05"), false
+		case ColumnTypeYear:
+			return def.Format("2006"), true
+		}
+		return def.Format("2006-01-02 15:04:05"), false
+	case string:
+		if c.Type == ColumnTypeBoolean {
+			if b, err := strconv.ParseBool(def); err == nil {
+				return ternary(b, "1", "0"), true
+			}
+		}
+		if _, err := strconv.ParseFloat(def, 64); err == nil && inArray(c.Type, numericTypes) {
+			return def, true
+		}
+		return def, false
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return convString(def), inArray(c.Type, numericTypes)
+	default:
+		return convString(def), false
+	}
+}
+
+// srid get the spatial reference system identifier of the column, ok is false without srid
+func (c *Column) srid() (int, bool) {
+	srid, ok := c.Attributes[ColumnAttrSrid].(int)
+	return srid, ok
+}
+
+// spatial check the column is a spatial column
+func (c *Column) spatial() bool {
+	return inArray(c.Type, spatialTypes)
+}
+
+// precision get the fractional seconds precision of the column, 0 is the database default
+func (c *Column) precision() int {
+	precision, _ := c.Attributes[ColumnAttrPrecision].(int)
+	return precision
+}
+
+// currentTimestamp get the CURRENT_TIMESTAMP expression with the precision of the column
+func (c *Column) currentTimestamp() string {
+	if precision := c.precision(); precision > 0 {
+		return "CURRENT_TIMESTAMP(" + strconv.Itoa(precision) + ")"
+	}
+	return "CURRENT_TIMESTAMP"
+}
+
+// generated get the expression and the storage of a generated column, the storage is virtual or stored,
+// or empty if the column is not generated
+func (c *Column) generated() (expression string, storage string) {
+	if expression, ok := c.Attributes[ColumnAttrStoredAs].(string); ok {
+		return expression, "stored"
+	}
+	if expression, ok := c.Attributes[ColumnAttrVirtualAs].(string); ok {
+		return expression, "virtual"
+	}
+	return "", ""
+}
+
+// compileGenerated compile the generated clause of a generated column
+func (c *Column) compileGenerated() string {
+	if expression, storage := c.generated(); storage != "" {
+		return " generated always as (" + expression + ") " + storage
+	}
+	return ""
+}
@@ -1,25 +1,45 @@
 package schema
 
 const (
-	commandCreate       = "create"
-	commandAdd          = "add"
-	commandChange       = "change"
-	commandDrop         = "drop"
-	commandDropColumn   = "dropColumn"
-	commandRename       = "rename"
-	commandRenameColumn = "renameColumn"
-	commandDropIfExists = "dropIfExists"
-	commandPrimary      = "primary"
-	commandUnique       = "unique"
-	commandIndex        = "index"
-	commandComment      = "tableComment"
+	commandCreate         = "create"
+	commandAdd            = "add"
+	commandChange         = "change"
+	commandDrop           = "drop"
+	commandDropColumn     = "dropColumn"
+	commandRename         = "rename"
+	commandRenameColumn   = "renameColumn"
+	commandReorderColumns = "reorderColumns"
+	commandDropIfExists   = "dropIfExists"
+	commandDropPrimary    = "dropPrimary"
+	commandDropUnique     = "dropUnique"
+	commandDropIndex      = "dropIndex"
+	commandRenameIndex    = "renameIndex"
+	commandPrimary        = "primary"
+	commandUnique         = "unique"
+	commandIndex          = "index"
+	commandComment        = "tableComment"
+	commandForeign        = "foreign"
+	commandDropForeign    = "dropForeign"
 
-	commandAttrIndex     = "index"
-	commandAttrAlgorithm = "algorithm"
-	commandAttrColumns   = "columns" // []string
-	commandAttrComment   = "comment"
-	commandAttrFrom      = "from" // rename from
-	commandAttrTo        = "to"   // rename to
+	commandFulltext         = "fullText"
+	commandDropFulltext     = "dropFullText"
+	commandSpatialIndex     = "spatialIndex"
+	commandDropSpatialIndex = "dropSpatialIndex"
+	commandCheck            = "check"
+	commandDropCheck        = "dropCheck"
+
+	commandAttrIndex      = "index"
+	commandAttrAlgorithm  = "algorithm"
+	commandAttrColumns    = "columns" // []string
+	commandAttrComment    = "comment"
+	commandAttrFrom       = "from"       // rename from
+	commandAttrTo         = "to"         // rename to
+	commandAttrReferences = "references" // []string foreign key referenced columns
+	commandAttrOn         = "on"         // foreign key referenced table
+	commandAttrOnDelete   = "onDelete"   // foreign key on delete action
+	commandAttrOnUpdate   = "onUpdate"   // foreign key on update action
+	commandAttrExpression = "expression" // check constraint expression
+	commandAttrParser     = "parser"     // fulltext index parser
 )
 
 const (
@@ -47,24 +67,53 @@ const (
 	ColumnTypeTimestamp  = "timestamp"
 	ColumnTypeYear       = "year"
 	ColumnTypeBinary     = "binary"
+	ColumnTypeVarBinary  = "varbinary"
+	ColumnTypeTinyBlob   = "tinyblob"
 	ColumnTypeBlob       = "blob"
+	ColumnTypeMediumBlob = "mediumblob"
+	ColumnTypeLongBlob   = "longblob"
 	ColumnTypeUuid       = "uuid"
 
-	ColumnAttrPrimary       = "primary"
-	ColumnAttrUnique        = "unique"
-	ColumnAttrIndex         = "index"
-	ColumnAttrComment       = "comment"       // 字段注释
-	ColumnAttrDefault       = "default"       // 字段默认值
-	ColumnAttrNullable      = "nullable"      // 是否可为空 bool
-	ColumnAttrLength        = "length"        // 字段长度
-	ColumnAttrTotal         = "total"         // 浮点数字段小数
-	ColumnAttrPlaces        = "places"        // 浮点数小数位
-	ColumnAttrAllowed       = "allowed"       // enum allowed []string
-	ColumnAttrChange        = "change"        // 是否是修改字段 bool
-	ColumnAttrAutoIncrement = "autoIncrement" // 是否自动递增 bool
-	ColumnAttrUnsigned      = "unsigned"      // 是否无符号 bool
-	ColumnAttrCharset       = "charset"       // 字符集
-	ColumnAttrCollate       = "collate"       // 排序规则
+	ColumnTypeGeometry           = "geometry"
+	ColumnTypePoint              = "point"
+	ColumnTypeLineString         = "linestring"
+	ColumnTypePolygon            = "polygon"
+	ColumnTypeMultiPoint         = "multipoint"
+	ColumnTypeMultiPolygon       = "multipolygon"
+	ColumnTypeGeometryCollection = "geometrycollection"
+
+	ColumnAttrPrimary            = "primary"
+	ColumnAttrUnique             = "unique"
+	ColumnAttrIndex              = "index"
+	ColumnAttrFulltext           = "fulltext"           // 全文索引 []IndexOption
+	ColumnAttrComment            = "comment"            // 字段注释
+	ColumnAttrDefault            = "default"            // 字段默认值
+	ColumnAttrNullable           = "nullable"           // 是否可为空 bool
+	ColumnAttrLength             = "length"             // 字段长度
+	ColumnAttrTotal              = "total"              // 浮点数字段小数
+	ColumnAttrPlaces             = "places"             // 浮点数小数位
+	ColumnAttrAllowed            = "allowed"            // enum allowed []string
+	ColumnAttrChange             = "change"             // 是否是修改字段 bool
+	ColumnAttrAutoIncrement      = "autoIncrement"      // 是否自动递增 bool
+	ColumnAttrUnsigned           = "unsigned"           // 是否无符号 bool
+	ColumnAttrCharset            = "charset"            // 字符集
+	ColumnAttrCollate            = "collate"            // 排序规则
+	ColumnAttrOn                 = "on"                 // 外键关联表
+	ColumnAttrPrecision          = "precision"          // 时间字段秒的小数位数 int
+	ColumnAttrUseCurrent         = "useCurrent"         // 默认值为当前时间 bool
+	ColumnAttrUseCurrentOnUpdate = "useCurrentOnUpdate" // 更新时设置为当前时间 bool
+	ColumnAttrVirtualAs          = "virtualAs"          // 虚拟生成列表达式
+	ColumnAttrStoredAs           = "storedAs"           // 存储生成列表达式
+	ColumnAttrAfter              = "after"              // 字段位于指定字段之后，仅 MySQL
+	ColumnAttrFirst              = "first"              // 字段位于第一列，仅 MySQL bool
+	ColumnAttrCheck              = "check"              // 字段的检查约束表达式
+	ColumnAttrSrid               = "srid"               // 空间字段的空间参考标识符 int
+)
+
+const (
+	DriverMysql    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSqlite   = "sqlite"
 )
 
 const (
@@ -73,6 +122,8 @@ const (
 	DefaultCharset      = "utf8mb4"            // 默认编码
 	DefaultCollation    = "utf8mb4_unicode_ci" // 默认排序
 	DefaultStringLength = 255                  // string 字段默认长度
+
+	DefaultMigrationTable = "migrations" // 迁移记录表
 )
 
 // Map alias
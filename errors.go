@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+)
+
+// the errors of the common mysql error codes, e.g. errors.Is(err, schema.ErrTableExists)
+var (
+	ErrTableExists      = errors.New("table already exists")     // 1050
+	ErrUnknownColumn    = errors.New("unknown column")           // 1054
+	ErrDuplicateColumn  = errors.New("duplicate column name")    // 1060
+	ErrDuplicateKeyName = errors.New("duplicate key name")       // 1061
+	ErrCantDrop         = errors.New("can't drop column or key") // 1091
+	ErrNoSuchTable      = errors.New("table doesn't exist")      // 1146
+)
+
+// mysqlErrors the errors of the mysql error codes
+var mysqlErrors = map[int]error{
+	1050: ErrTableExists,
+	1054: ErrUnknownColumn,
+	1060: ErrDuplicateColumn,
+	1061: ErrDuplicateKeyName,
+	1091: ErrCantDrop,
+	1146: ErrNoSuchTable,
+}
+
+// StatementError the error of a failed statement
+type StatementError struct {
+	SQL     string // the failed statement
+	Index   int    // the index of the statement in the executed statements
+	Table   string // table name without prefix
+	Command string // the blueprint command which compiles the statement
+	Code    int    // the mysql error code, 0 if unknown
+	Err     error  // the driver error
+}
+
+func (e *StatementError) Error() string {
+	return fmt.Sprintf("schema err: statement %d (%s %s) failed: %v, sql: %s", e.Index, e.Command, e.Table, e.Err, e.SQL)
+}
+
+func (e *StatementError) Unwrap() error {
+	return e.Err
+}
+
+// Is match the sentinel error of the mysql error code
+func (e *StatementError) Is(target error) bool {
+	sentinel, ok := mysqlErrors[e.Code]
+	return ok && sentinel == target
+}
+
+// newStatementError wrap the driver error of the statement
+func newStatementError(statement *Statement, index int, err error) *StatementError {
+	return &StatementError{
+		SQL:     statement.SQL,
+		Index:   index,
+		Table:   statement.Table,
+		Command: statement.Command,
+		Code:    mysqlErrorCode(err),
+		Err:     err,
+	}
+}
+
+var mysqlErrorMessage = regexp.MustCompile(`^Error (\d+)`)
+
+// mysqlErrorCode get the error code of a mysql driver error:
+// the Number field of github.com/go-sql-driver/mysql.MySQLError, or the code of the message "Error 1050 (42S01): ..."
+func mysqlErrorCode(err error) int {
+	for ; err != nil; err = errors.Unwrap(err) {
+		value := reflect.Indirect(reflect.ValueOf(err))
+		if value.Kind() == reflect.Struct {
+			if number := value.FieldByName("Number"); number.IsValid() && number.CanUint() {
+				return int(number.Uint())
+			}
+		}
+
+		if match := mysqlErrorMessage.FindStringSubmatch(err.Error()); match != nil {
+			code, _ := strconv.Atoi(match[1])
+			return code
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,69 @@
+package schema
+
+const (
+	ForeignActionCascade    = "cascade"
+	ForeignActionSetNull    = "set null"
+	ForeignActionSetDefault = "set default"
+	ForeignActionRestrict   = "restrict"
+	ForeignActionNoAction   = "no action"
+)
+
+// ForeignKeyDefinition fluent foreign key definition
+type ForeignKeyDefinition struct {
+	command *Command
+}
+
+// References set the referenced columns, columns can string or []string, default id
+func (f *ForeignKeyDefinition) References(columns interface{}) *ForeignKeyDefinition {
+	f.command.Attributes[commandAttrReferences] = toStrings(columns)
+	return f
+}
+
+// On set the referenced table, without prefix
+func (f *ForeignKeyDefinition) On(table string) *ForeignKeyDefinition {
+	f.command.Attributes[commandAttrOn] = table
+	return f
+}
+
+// OnDelete set the on delete action
+func (f *ForeignKeyDefinition) OnDelete(action string) *ForeignKeyDefinition {
+	f.command.Attributes[commandAttrOnDelete] = action
+	return f
+}
+
+// OnUpdate set the on update action
+func (f *ForeignKeyDefinition) OnUpdate(action string) *ForeignKeyDefinition {
+	f.command.Attributes[commandAttrOnUpdate] = action
+	return f
+}
+
+// CascadeOnDelete delete the rows when the referenced row is deleted
+func (f *ForeignKeyDefinition) CascadeOnDelete() *ForeignKeyDefinition {
+	return f.OnDelete(ForeignActionCascade)
+}
+
+// NullOnDelete set the columns null when the referenced row is deleted
+func (f *ForeignKeyDefinition) NullOnDelete() *ForeignKeyDefinition {
+	return f.OnDelete(ForeignActionSetNull)
+}
+
+// RestrictOnDelete prevent deleting the referenced row
+func (f *ForeignKeyDefinition) RestrictOnDelete() *ForeignKeyDefinition {
+	return f.OnDelete(ForeignActionRestrict)
+}
+
+// CascadeOnUpdate update the columns when the referenced columns are updated
+func (f *ForeignKeyDefinition) CascadeOnUpdate() *ForeignKeyDefinition {
+	return f.OnUpdate(ForeignActionCascade)
+}
+
+// Name set the constraint name, default {prefix}{table}_{columns}_foreign
+func (f *ForeignKeyDefinition) Name(name string) *ForeignKeyDefinition {
+	f.command.Attributes[commandAttrIndex] = name
+	return f
+}
+
+// GetCommand get the foreign command
+func (f *ForeignKeyDefinition) GetCommand() *Command {
+	return f.command
+}
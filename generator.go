@@ -0,0 +1,411 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// GenerateOption options of Schema.Generate
+type GenerateOption struct {
+	Package string   // package name of the generated file, default migrations
+	Func    string   // function name of the generated file, default CreateTables
+	Tables  []string // table names or patterns without prefix, e.g. user_*, default all tables
+}
+
+// tableDefinition the definition of an existing table
+type tableDefinition struct {
+	name        string // table name without prefix
+	comment     string
+	columns     []*ColumnInfo
+	indexes     []*IndexInfo
+	foreignKeys []*ForeignKeyInfo
+}
+
+// Generate Write the go source of a function which creates the tables of the database by Schema.Create,
+// the referenced tables of foreign keys are created first.
+func (s *Schema) Generate(w io.Writer, option ...GenerateOption) error {
+	opt := varDef(option)
+	opt.Package = ternary(opt.Package == "", "migrations", opt.Package)
+	opt.Func = ternary(opt.Func == "", "CreateTables", opt.Func)
+
+	tables, err := s.GetTables()
+	if err != nil {
+		return err
+	}
+
+	var definitions []*tableDefinition
+
+	for _, table := range tables {
+		if !strings.HasPrefix(table.Name, s.config.Prefix) {
+			continue
+		}
+
+		definition := &tableDefinition{
+			name:    strings.TrimPrefix(table.Name, s.config.Prefix),
+			comment: table.Comment,
+		}
+
+		if !matchTable(definition.name, opt.Tables) {
+			continue
+		}
+
+		if definition.columns, err = s.GetColumns(definition.name); err != nil {
+			return err
+		}
+		if definition.indexes, err = s.GetIndexes(definition.name); err != nil {
+			return err
+		}
+		if definition.foreignKeys, err = s.GetForeignKeys(definition.name); err != nil {
+			return err
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "package %s\n\nimport \"github.com/chenpkg/schema\"\n\n", opt.Package)
+	fmt.Fprintf(&buf, "// %s create the tables\nfunc %s(s *schema.Schema) error {\n", opt.Func, opt.Func)
+	for _, definition := range s.sortTables(definitions) {
+		buf.WriteString(s.generateTable(definition))
+	}
+	buf.WriteString("return nil\n}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("schema err: format generated source: %w", err)
+	}
+
+	_, err = w.Write(source)
+
+	return err
+}
+
+// matchTable check the table matches one of the names or patterns, all tables match empty patterns
+func matchTable(table string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, table); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortTables sort the tables so that the referenced tables of foreign keys are created first
+func (s *Schema) sortTables(definitions []*tableDefinition) (sorted []*tableDefinition) {
+	created := map[string]bool{}
+
+	for len(definitions) > 0 {
+		var rest []*tableDefinition
+
+		for _, definition := range definitions {
+			ready := true
+			for _, foreignKey := range definition.foreignKeys {
+				on := strings.TrimPrefix(foreignKey.ForeignTable, s.config.Prefix)
+				if on != definition.name && !created[on] && s.pending(on, definitions) {
+					ready = false
+				}
+			}
+
+			if ready {
+				created[definition.name] = true
+				sorted = append(sorted, definition)
+			} else {
+				rest = append(rest, definition)
+			}
+		}
+
+		// circular foreign keys
+		if len(rest) == len(definitions) {
+			return append(sorted, rest...)
+		}
+		definitions = rest
+	}
+
+	return
+}
+
+// pending check the table is one of the definitions
+func (s *Schema) pending(table string, definitions []*tableDefinition) bool {
+	for _, definition := range definitions {
+		if definition.name == table {
+			return true
+		}
+	}
+	return false
+}
+
+// generateTable generate the Schema.Create call of a table
+func (s *Schema) generateTable(definition *tableDefinition) string {
+	var (
+		b         strings.Builder
+		modifiers = map[string]string{}
+		explicit  []string
+		increment = false
+		blueprint = &Blueprint{table: definition.name, config: s.config}
+	)
+
+	for _, column := range definition.columns {
+		increment = increment || column.AutoIncrement
+	}
+
+	// single column indexes with the default name are column modifiers,
+	// the other indexes are blueprint indexes with the name
+	for _, index := range definition.indexes {
+		if (index.Primary && increment) || backsForeignKey(index, definition.foreignKeys) {
+			continue
+		}
+
+		var method, t string
+		switch {
+		case index.Type == "fulltext" || index.Type == "spatial":
+			method = ternary(index.Type == "fulltext", "Fulltext", "SpatialIndex")
+			t = ternary(index.Type == "fulltext", commandFulltext, commandSpatialIndex)
+		case index.Type != "" && index.Type != "btree" && index.Type != "hash":
+			continue
+		default:
+			method = ternary(index.Primary, "Primary", ternary(index.Unique, "Unique", "Index"))
+			t = ternary(index.Primary, commandPrimary, ternary(index.Unique, commandUnique, commandIndex))
+		}
+
+		// the name of the primary key is given by the database, the sqlite unique constraints are named by sqlite
+		name := ""
+		if !index.Primary && !strings.HasPrefix(index.Name, "sqlite_autoindex_") && index.Name != blueprint.createIndexName(t, index.Columns) {
+			name = fmt.Sprintf(".Name(%s)", strconv.Quote(index.Name))
+		}
+
+		if _, ok := modifiers[index.Columns[0]]; len(index.Columns) == 1 && !ok && name == "" && t != commandFulltext && t != commandSpatialIndex {
+			modifiers[index.Columns[0]] = "." + method + "()"
+			continue
+		}
+
+		explicit = append(explicit, fmt.Sprintf("table.%s(%s)%s\n", method, goStrings(index.Columns), name))
+	}
+
+	fmt.Fprintf(&b, "if err := s.Create(%s, func(table *schema.Blueprint) {\n", strconv.Quote(definition.name))
+
+	columns := definition.columns
+	for i := 0; i < len(columns); i++ {
+		column := columns[i]
+
+		if i+1 < len(columns) && column.Name == "created_at" && columns[i+1].Name == "updated_at" &&
+			s.plainTimestamp(column) && s.plainTimestamp(columns[i+1]) {
+			b.WriteString("table.Timestamps()\n")
+			i++
+			continue
+		}
+
+		if column.Name == "deleted_at" && s.plainTimestamp(column) {
+			b.WriteString("table.SoftDeletes()\n")
+			continue
+		}
+
+		b.WriteString(s.generateColumn(column, modifiers[column.Name]))
+	}
+
+	for _, code := range explicit {
+		b.WriteString(code)
+	}
+
+	for _, foreignKey := range definition.foreignKeys {
+		b.WriteString(s.generateForeignKey(definition.name, foreignKey))
+	}
+
+	if definition.comment != "" {
+		fmt.Fprintf(&b, "table.Comment(%s)\n", strconv.Quote(definition.comment))
+	}
+
+	b.WriteString("}); err != nil {\nreturn err\n}\n\n")
+
+	return b.String()
+}
+
+// plainTimestamp check the column is a nullable timestamp without default and comment
+func (s *Schema) plainTimestamp(info *ColumnInfo) bool {
+	column := info.toColumn()
+	return column.Type == ColumnTypeTimestamp && info.Nullable && info.Comment == "" &&
+		column.Attributes[ColumnAttrDefault] == nil && column.Attributes[ColumnAttrUseCurrent] == nil &&
+		column.Attributes[ColumnAttrUseCurrentOnUpdate] == nil && column.Attributes[ColumnAttrPrecision] == nil
+}
+
+// generateColumn generate the column definition with modifiers
+func (s *Schema) generateColumn(info *ColumnInfo, modifier string) string {
+	var (
+		column   = info.toColumn()
+		name     = strconv.Quote(column.Name)
+		unsigned = column.Attributes[ColumnAttrUnsigned] == true
+		code     string
+	)
+
+	switch column.Type {
+	case ColumnTypeChar:
+		code = fmt.Sprintf("Char(%s, %d)", name, column.Attributes[ColumnAttrLength])
+	case ColumnTypeVarchar:
+		if length := column.Attributes[ColumnAttrLength].(int); length != DefaultStringLength {
+			code = fmt.Sprintf("String(%s, %d)", name, length)
+		} else {
+			code = fmt.Sprintf("String(%s)", name)
+		}
+	case ColumnTypeTinyInt, ColumnTypeSmallInt, ColumnTypeMediumInt, ColumnTypeInt, ColumnTypeBigInt:
+		method := map[string]string{
+			ColumnTypeTinyInt:   "TinyInt",
+			ColumnTypeSmallInt:  "SmallInt",
+			ColumnTypeMediumInt: "MediumInt",
+			ColumnTypeInt:       "Int",
+			ColumnTypeBigInt:    "BigInt",
+		}[column.Type]
+
+		switch {
+		case info.AutoIncrement && unsigned && column.Type == ColumnTypeBigInt:
+			code = ternary(column.Name == "id", "Id()", fmt.Sprintf("BigIncrements(%s)", name))
+		case info.AutoIncrement && unsigned && column.Type == ColumnTypeInt:
+			code = fmt.Sprintf("Increments(%s)", name)
+		case info.AutoIncrement:
+			code = fmt.Sprintf("%s(%s, true)", ternary(unsigned, "Unsigned"+method, method), name)
+		default:
+			code = fmt.Sprintf("%s(%s)", ternary(unsigned, "Unsigned"+method, method), name)
+		}
+	case ColumnTypeFloat, ColumnTypeDouble, ColumnTypeDecimal:
+		method := ternary(column.Type == ColumnTypeDecimal, "Decimal", "Double")
+		if _, ok := column.Attributes[ColumnAttrTotal]; ok {
+			code = fmt.Sprintf("%s(%s, %d, %d)", ternary(unsigned, "Unsigned"+method, method), name,
+				column.Attributes[ColumnAttrTotal], column.Attributes[ColumnAttrPlaces])
+		} else {
+			code = s.generateAddColumn(column.Type, name, unsigned)
+		}
+	case ColumnTypeBinary, ColumnTypeVarBinary:
+		method := ternary(column.Type == ColumnTypeBinary, "Binary", "VarBinary")
+		if length, ok := column.Attributes[ColumnAttrLength]; ok {
+			code = fmt.Sprintf("%s(%s, %d)", method, name, length)
+		} else {
+			code = fmt.Sprintf("%s(%s)", method, name)
+		}
+	case ColumnTypeTinyBlob, ColumnTypeBlob, ColumnTypeMediumBlob, ColumnTypeLongBlob:
+		method := map[string]string{
+			ColumnTypeTinyBlob:   "TinyBlob",
+			ColumnTypeBlob:       "Blob",
+			ColumnTypeMediumBlob: "MediumBlob",
+			ColumnTypeLongBlob:   "LongBlob",
+		}[column.Type]
+		code = fmt.Sprintf("%s(%s)", method, name)
+	case ColumnTypeEnum, ColumnTypeSet:
+		code = fmt.Sprintf("%s(%s, %s)", ucFirst(column.Type), name, goStrings(column.Attributes[ColumnAttrAllowed].([]string)))
+	case ColumnTypeDateTime, ColumnTypeTime, ColumnTypeTimestamp:
+		method := ternary(column.Type == ColumnTypeDateTime, "DateTime", ucFirst(column.Type))
+		if precision := column.precision(); precision > 0 {
+			code = fmt.Sprintf("%s(%s, %d)", method, name, precision)
+		} else {
+			code = fmt.Sprintf("%s(%s)", method, name)
+		}
+	case ColumnTypeBoolean, ColumnTypeTinyText, ColumnTypeText, ColumnTypeMediumText, ColumnTypeLongText,
+		ColumnTypeJson, ColumnTypeDate, ColumnTypeYear, ColumnTypeUuid:
+		method := map[string]string{
+			ColumnTypeTinyText:   "TinyText",
+			ColumnTypeMediumText: "MediumText",
+			ColumnTypeLongText:   "LongText",
+		}[column.Type]
+		code = fmt.Sprintf("%s(%s)", ternary(method == "", ucFirst(column.Type), method), name)
+	case ColumnTypeGeometry, ColumnTypePoint, ColumnTypeLineString, ColumnTypePolygon,
+		ColumnTypeMultiPoint, ColumnTypeMultiPolygon, ColumnTypeGeometryCollection:
+		method := map[string]string{
+			ColumnTypeLineString:         "LineString",
+			ColumnTypeMultiPoint:         "MultiPoint",
+			ColumnTypeMultiPolygon:       "MultiPolygon",
+			ColumnTypeGeometryCollection: "GeometryCollection",
+		}[column.Type]
+		method = ternary(method == "", ucFirst(column.Type), method)
+		if srid, ok := column.srid(); ok {
+			code = fmt.Sprintf("%s(%s, %d)", method, name, srid)
+		} else {
+			code = fmt.Sprintf("%s(%s)", method, name)
+		}
+	default:
+		// the types without a blueprint method are added by the full column type
+		code = s.generateAddColumn(column.Type, name, unsigned)
+	}
+
+	if info.Nullable {
+		code += ".Nullable()"
+	}
+
+	if expression, storage := column.generated(); storage != "" {
+		code += fmt.Sprintf(".%s(%s)", ternary(storage == "stored", "StoredAs", "VirtualAs"), strconv.Quote(expression))
+	}
+
+	if column.Attributes[ColumnAttrUseCurrent] == true {
+		code += ".UseCurrent()"
+	}
+
+	if column.Attributes[ColumnAttrUseCurrentOnUpdate] == true {
+		code += ".UseCurrentOnUpdate()"
+	}
+
+	switch def := column.Attributes[ColumnAttrDefault].(type) {
+	case Expression:
+		code += fmt.Sprintf(".Default(schema.Raw(%s))", strconv.Quote(string(def)))
+	case string:
+		code += fmt.Sprintf(".Default(%s)", strconv.Quote(def))
+	}
+
+	if charset := ternary(s.config.Charset == "", DefaultCharset, s.config.Charset); info.Charset != "" && info.Charset != charset {
+		code += fmt.Sprintf(".Charset(%s)", strconv.Quote(info.Charset))
+	}
+
+	if collation := ternary(s.config.Collation == "", DefaultCollation, s.config.Collation); info.Collation != "" && info.Collation != collation {
+		code += fmt.Sprintf(".Collation(%s)", strconv.Quote(info.Collation))
+	}
+
+	if info.Comment != "" {
+		code += fmt.Sprintf(".Comment(%s)", strconv.Quote(info.Comment))
+	}
+
+	return "table." + code + modifier + "\n"
+}
+
+// generateAddColumn generate the AddColumn call of a column type without a blueprint method
+func (s *Schema) generateAddColumn(types string, name string, unsigned bool) string {
+	if unsigned {
+		return fmt.Sprintf("AddColumn(%s, %s, schema.Map{schema.ColumnAttrUnsigned: true})", strconv.Quote(types), name)
+	}
+	return fmt.Sprintf("AddColumn(%s, %s)", strconv.Quote(types), name)
+}
+
+// generateForeignKey generate the foreign key definition
+func (s *Schema) generateForeignKey(table string, foreignKey *ForeignKeyInfo) string {
+	code := fmt.Sprintf("table.Foreign(%s).References(%s).On(%s)",
+		goStrings(foreignKey.Columns),
+		goStrings(foreignKey.ForeignColumns),
+		strconv.Quote(strings.TrimPrefix(foreignKey.ForeignTable, s.config.Prefix)))
+
+	if name := (&Blueprint{table: table, config: s.config}).createIndexName(commandForeign, foreignKey.Columns); foreignKey.Name != "" && foreignKey.Name != name {
+		code += fmt.Sprintf(".Name(%s)", strconv.Quote(foreignKey.Name))
+	}
+
+	actions := [][2]string{{"OnDelete", foreignKey.OnDelete}, {"OnUpdate", foreignKey.OnUpdate}}
+	for _, action := range actions {
+		if action[1] != "" && action[1] != ForeignActionNoAction && action[1] != ForeignActionRestrict {
+			code += fmt.Sprintf(".%s(%s)", action[0], strconv.Quote(action[1]))
+		}
+	}
+
+	return code + "\n"
+}
+
+// goStrings generate a string or a []string literal
+func goStrings(values []string) string {
+	if len(values) == 1 {
+		return strconv.Quote(values[0])
+	}
+
+	return "[]string{" + strings.Join(arrMap(values, strconv.Quote), ", ") + "}"
+}
@@ -0,0 +1,160 @@
+package schema
+
+import (
+	"context"
+	"go/format"
+	"strings"
+	"testing"
+)
+
+func TestSchema_generateTable(t *testing.T) {
+	var (
+		def        = "0"
+		now        = "current_timestamp()"
+		expression = "(json_array())"
+		definition = &tableDefinition{
+			name:    "posts",
+			comment: "文章",
+			columns: []*ColumnInfo{
+				{Name: "id", Type: "bigint unsigned", AutoIncrement: true},
+				{Name: "user_id", Type: "bigint unsigned"},
+				{Name: "title", Type: "varchar(100)", Charset: "utf8mb4", Collation: "utf8mb4_unicode_ci", Comment: "标题"},
+				{Name: "slug", Type: "varchar(255)", Charset: "utf8mb4", Collation: "utf8mb4_bin"},
+				{Name: "title_length", Type: "int", Generated: "virtual", Expression: "char_length(`title`)"},
+				{Name: "price", Type: "decimal(10,2) unsigned", Default: &def},
+				{Name: "status", Type: "enum('draft','published')"},
+				{Name: "views", Type: "int(10) unsigned", Nullable: true},
+				{Name: "flag", Type: "tinyint(1)"},
+				{Name: "tags", Type: "json", Default: &expression},
+				{Name: "published_at", Type: "timestamp(3)", Default: &now, OnUpdate: "CURRENT_TIMESTAMP(3)"},
+				{Name: "created_at", Type: "timestamp", Nullable: true},
+				{Name: "updated_at", Type: "timestamp", Nullable: true},
+				{Name: "deleted_at", Type: "timestamp", Nullable: true},
+				{Name: "location", Type: "point"},
+				{Name: "area", Type: "geometry(polygon,4326)", Nullable: true},
+			},
+			indexes: []*IndexInfo{
+				{Name: "PRIMARY", Columns: []string{"id"}, Type: "btree", Unique: true, Primary: true},
+				{Name: "posts_slug_unique", Columns: []string{"slug"}, Type: "btree", Unique: true},
+				{Name: "posts_status_views_index", Columns: []string{"status", "views"}, Type: "btree"},
+				{Name: "posts_user_id_foreign", Columns: []string{"user_id"}, Type: "btree"},
+				{Name: "posts_title_fulltext", Columns: []string{"title"}, Type: "fulltext"},
+				{Name: "posts_location_spatialindex", Columns: []string{"location"}, Type: "spatial"},
+			},
+			foreignKeys: []*ForeignKeyInfo{
+				{Name: "posts_user_id_foreign", Columns: []string{"user_id"}, ForeignTable: "users", ForeignColumns: []string{"id"}, OnUpdate: "no action", OnDelete: "cascade"},
+			},
+		}
+		code = `if err := s.Create("posts", func(table *schema.Blueprint) {
+table.Id()
+table.UnsignedBigInt("user_id")
+table.String("title", 100).Comment("标题")
+table.String("slug").Collation("utf8mb4_bin").Unique()
+table.Int("title_length").VirtualAs("char_length(` + "`title`" + `)")
+table.UnsignedDecimal("price", 10, 2).Default("0")
+table.Enum("status", []string{"draft", "published"})
+table.UnsignedInt("views").Nullable()
+table.Boolean("flag")
+table.Json("tags").Default(schema.Raw("(json_array())"))
+table.Timestamp("published_at", 3).UseCurrent().UseCurrentOnUpdate()
+table.Timestamps()
+table.SoftDeletes()
+table.Point("location")
+table.Polygon("area", 4326).Nullable()
+table.Index([]string{"status", "views"})
+table.Fulltext("title")
+table.SpatialIndex("location")
+table.Foreign("user_id").References("id").On("users").OnDelete("cascade")
+table.Comment("文章")
+}); err != nil {
+return err
+}
+
+`
+	)
+
+	newSchema := NewSchema(context.Background(), &Config{})
+
+	gen := newSchema.generateTable(definition)
+	if gen != code {
+		t.Fatal("generateTable err:", "\ncode:", code, "\ngen:", gen)
+	}
+
+	if _, err := format.Source([]byte("package migrations\nfunc up() error {\n" + gen + "return nil\n}\n")); err != nil {
+		t.Fatal("generateTable err:", err)
+	}
+}
+
+func TestSchema_generateTable_types(t *testing.T) {
+	var (
+		definition = &tableDefinition{
+			name: "files",
+			columns: []*ColumnInfo{
+				{Name: "id", Type: "bigint unsigned", AutoIncrement: true},
+				{Name: "status", Type: "enum('Draft','Published')"},
+				{Name: "data", Type: "blob"},
+				{Name: "payload", Type: "longblob", Nullable: true},
+				{Name: "hash", Type: "varbinary(32)"},
+				{Name: "digest", Type: "binary(16)"},
+				{Name: "score", Type: "double"},
+				{Name: "ratio", Type: "double(8,3)"},
+				{Name: "flags", Type: "bit(8)"},
+			},
+			indexes: []*IndexInfo{
+				{Name: "PRIMARY", Columns: []string{"id"}, Type: "btree", Unique: true, Primary: true},
+				{Name: "uk_files_hash", Columns: []string{"hash"}, Type: "btree", Unique: true},
+				{Name: "files_digest_index", Columns: []string{"digest"}, Type: "btree"},
+				{Name: "idx_status_score", Columns: []string{"status", "score"}, Type: "btree"},
+			},
+		}
+		code = `if err := s.Create("files", func(table *schema.Blueprint) {
+table.Id()
+table.Enum("status", []string{"Draft", "Published"})
+table.Blob("data")
+table.LongBlob("payload").Nullable()
+table.VarBinary("hash", 32)
+table.Binary("digest", 16).Index()
+table.AddColumn("double", "score")
+table.Double("ratio", 8, 3)
+table.AddColumn("bit(8)", "flags")
+table.Unique("hash").Name("uk_files_hash")
+table.Index([]string{"status", "score"}).Name("idx_status_score")
+}); err != nil {
+return err
+}
+
+`
+	)
+
+	newSchema := NewSchema(context.Background(), &Config{})
+
+	if gen := newSchema.generateTable(definition); gen != code {
+		t.Fatal("generateTable err:", "\ncode:", code, "\ngen:", gen)
+	}
+
+	// the generated table compiles to the same column types
+	blueprint := NewBlueprint(newSchema, "files", func(table *Blueprint) {
+		table.create()
+		table.Id()
+		table.Enum("status", []string{"Draft", "Published"})
+		table.Blob("data")
+		table.LongBlob("payload").Nullable()
+		table.VarBinary("hash", 32)
+		table.Binary("digest", 16).Index()
+		table.AddColumn("double", "score")
+		table.Double("ratio", 8, 3)
+		table.AddColumn("bit(8)", "flags")
+		table.Unique("hash").Name("uk_files_hash")
+		table.Index([]string{"status", "score"}).Name("idx_status_score")
+	})
+	sql, err := blueprint.ToSql(newSchema.GetGrammar())
+	expected := []string{
+		"create table `files` (`id` bigint unsigned not null auto_increment primary key, `status` enum('Draft', 'Published') not null, `data` blob not null, `payload` longblob null, `hash` varbinary(32) not null, `digest` binary(16) not null, `score` double not null, `ratio` double(8, 3) not null, `flags` bit(8) not null) default character set utf8mb4 collate 'utf8mb4_unicode_ci' engine = InnoDB",
+		"alter table `files` add unique `uk_files_hash`(`hash`)",
+		"alter table `files` add index `idx_status_score`(`status`, `score`)",
+		"alter table `files` add index `files_digest_index`(`digest`)",
+	}
+	if err != nil || strings.Join(sql, "\n") != strings.Join(expected, "\n") {
+		t.Fatal("generateTable err:", err, "\nsql:", expected, "\ngen:", sql)
+	}
+}
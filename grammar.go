@@ -2,20 +2,168 @@ package schema
 
 import (
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 )
 
-var localGrammar = &Grammar{}
+var localGrammar = &MysqlGrammar{}
 
-type Grammar struct{}
+// Grammar compile blueprint commands into the sql of a database dialect.
+// Every command is compiled by the Compile<Command> method of the grammar,
+// e.g. CompileCreate, which returns a statement or a list of statements, and optionally an error.
+type Grammar interface {
+	// Compile compile a command into sql statements, an error is returned when the grammar
+	// does not support the command
+	Compile(blueprint *Blueprint, command *Command) ([]string, error)
+	// GetType get the column type definition
+	GetType(column *Column) string
+	// GetColumns get the definitions of the added columns, with modifiers
+	GetColumns(blueprint *Blueprint) []string
+	// GetChangeColumns get the definitions of the changed columns, with modifiers
+	GetChangeColumns(blueprint *Blueprint) []string
+	// CompileTableExists Compile the query to determine if a table exists
+	CompileTableExists(database, table string) (string, []interface{})
+	// CompileTables Compile the query to get the tables of a database, ordered by name,
+	// rows are: name, comment
+	CompileTables(database string) (string, []interface{})
+	// CompileColumns Compile the query to get the columns of a table, ordered by position,
+	// rows are: name, type_name, type, length, nullable, default, charset, collation, comment, auto_increment, position,
+	// on_update, generated, expression
+	CompileColumns(database, table string) (string, []interface{})
+	// CompileIndexes Compile the query to get the indexes of a table, a row per index column,
+	// rows are: name, column, type, unique, primary, position in the index start from 1
+	CompileIndexes(database, table string) (string, []interface{})
+	// CompileForeignKeys Compile the query to get the foreign keys of a table, a row per key column,
+	// rows are: name, column, foreign_table, foreign_column, on_update, on_delete, position in the key start from 1
+	CompileForeignKeys(database, table string) (string, []interface{})
+	// CompileChecks Compile the query to get the check constraints of a table, ordered by name,
+	// rows are: name, expression
+	CompileChecks(database, table string) (string, []interface{})
+	// Placeholder get the bind parameter placeholder of the n-th argument, start from 1
+	Placeholder(n int) string
+	// Wrap wrap an identifier such as a table name, the quotes in the identifier are escaped
+	Wrap(value string) string
+}
+
+// preparer a grammar that needs to inspect the database before compiling a blueprint
+type preparer interface {
+	prepare(blueprint *Blueprint) error
+}
+
+// validator a grammar that rejects the blueprint which the database can not execute
+type validator interface {
+	validate(blueprint *Blueprint) error
+}
+
+// checkParser a grammar that parses the check constraints from the create table statement
+type checkParser interface {
+	parseChecks(create string) []*CheckInfo
+}
+
+// rebuilder a grammar that compiles some commands by rebuilding the table,
+// which drops the table after copying the rows
+type rebuilder interface {
+	rebuilding(blueprint *Blueprint, command *Command) (rebuild bool, first bool)
+}
+
+// newGrammar get the grammar of config
+func newGrammar(config *Config) Grammar {
+	if config.Grammar != nil {
+		return config.Grammar
+	}
+
+	switch config.Driver {
+	case DriverPostgres:
+		return &PostgresGrammar{}
+	case DriverSqlite:
+		return &SqliteGrammar{}
+	default:
+		return localGrammar
+	}
+}
+
+// compile call the Compile<Command> method of grammar, the method returns a string or []string,
+// and optionally an error as the second result
+func compile(grammar Grammar, blueprint *Blueprint, command *Command) ([]string, error) {
+	var (
+		value   = reflect.ValueOf(grammar)
+		name    = "Compile" + ucFirst(command.Name)
+		compile = value.MethodByName(name)
+	)
+
+	if !compile.IsValid() {
+		return nil, fmt.Errorf("schema err: %s does not support %s command of %s", grammarName(grammar), command.Name, blueprint.GetTable())
+	}
+
+	results := compile.Call([]reflect.Value{
+		reflect.ValueOf(blueprint),
+		reflect.ValueOf(command),
+	})
+
+	if len(results) > 1 {
+		if err, _ := results[1].Interface().(error); err != nil {
+			return nil, err
+		}
+	}
+
+	switch sql := results[0].Interface().(type) {
+	case string:
+		if sql == "" {
+			return nil, nil
+		}
+		return []string{sql}, nil
+	case []string:
+		return sql, nil
+	}
+
+	return nil, nil
+}
+
+// grammarName get the dialect name of grammar for the error messages, e.g. sqlite
+func grammarName(grammar Grammar) string {
+	switch grammar.(type) {
+	case *MysqlGrammar:
+		return DriverMysql
+	case *PostgresGrammar:
+		return DriverPostgres
+	case *SqliteGrammar:
+		return DriverSqlite
+	}
+	return fmt.Sprintf("%T", grammar)
+}
+
+// baseGrammar methods shared by all grammars
+type baseGrammar struct{}
+
+// quote Quote the given string literal, the single quotes are doubled.
+func (g *baseGrammar) quote(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+// quoteString Quote the given string literals.
+func (g *baseGrammar) quoteString(value []string) string {
+	return strings.Join(arrMap(value, g.quote), ", ")
+}
+
+// prefixStrings
+func (g *baseGrammar) prefixStrings(prefix string, values []string) []string {
+	return arrMap(values, func(v string) string {
+		return prefix + v
+	})
+}
+
+// MysqlGrammar mysql grammar
+type MysqlGrammar struct {
+	baseGrammar
+}
 
 // GetColumns get add columns
-func (g *Grammar) GetColumns(blueprint *Blueprint) []string {
+func (g *MysqlGrammar) GetColumns(blueprint *Blueprint) []string {
 	var columns []string
 
 	for _, column := range blueprint.getAddedColumns() {
-		sql := "`" + column.Name + "` " + g.GetType(column)
+		sql := g.wrap(column.Name) + " " + g.GetType(column)
 		columns = append(columns, g.addModifiers(sql, blueprint, column))
 	}
 
@@ -23,16 +171,16 @@ func (g *Grammar) GetColumns(blueprint *Blueprint) []string {
 }
 
 // GetChangeColumns get change columns
-func (g *Grammar) GetChangeColumns(blueprint *Blueprint) (columns []string) {
+func (g *MysqlGrammar) GetChangeColumns(blueprint *Blueprint) (columns []string) {
 	for _, column := range blueprint.getChangedColumns() {
-		sql := "`" + column.Name + "` " + g.GetType(column)
+		sql := g.wrap(column.Name) + " " + g.GetType(column)
 		columns = append(columns, g.addModifiers(sql, blueprint, column))
 	}
 	return
 }
 
 // GetType get column type
-func (g *Grammar) GetType(column *Column) string {
+func (g *MysqlGrammar) GetType(column *Column) string {
 	switch column.Type {
 	case ColumnTypeChar:
 		return column.Type + "(" + strconv.Itoa(column.Attributes[ColumnAttrLength].(int)) + ")"
@@ -40,19 +188,36 @@ func (g *Grammar) GetType(column *Column) string {
 	case ColumnTypeVarchar:
 		return column.Type + "(" + strconv.Itoa(column.Attributes[ColumnAttrLength].(int)) + ")"
 
-	case ColumnTypeTinyText, ColumnTypeText, ColumnTypeMediumText, ColumnTypeLongText, ColumnTypeBigInt, ColumnTypeInt, ColumnTypeMediumInt, ColumnTypeTinyInt, ColumnTypeSmallInt, ColumnTypeJson, ColumnTypeDate, ColumnTypeDateTime, ColumnTypeTime, ColumnTypeTimestamp, ColumnTypeYear, ColumnTypeBinary, ColumnTypeBlob:
+	case ColumnTypeTinyText, ColumnTypeText, ColumnTypeMediumText, ColumnTypeLongText, ColumnTypeBigInt, ColumnTypeInt, ColumnTypeMediumInt, ColumnTypeTinyInt, ColumnTypeSmallInt, ColumnTypeJson, ColumnTypeDate, ColumnTypeYear,
+		ColumnTypeTinyBlob, ColumnTypeBlob, ColumnTypeMediumBlob, ColumnTypeLongBlob:
+		return column.Type
+
+	case ColumnTypeBinary, ColumnTypeVarBinary:
+		if length, ok := column.Attributes[ColumnAttrLength].(int); ok {
+			return column.Type + "(" + strconv.Itoa(length) + ")"
+		}
+		return column.Type
+
+	case ColumnTypeDateTime, ColumnTypeTime, ColumnTypeTimestamp:
+		if precision := column.precision(); precision > 0 {
+			return column.Type + "(" + strconv.Itoa(precision) + ")"
+		}
 		return column.Type
 
 	case ColumnTypeFloat, ColumnTypeDouble, ColumnTypeDecimal:
+		// an inspected column without the precision keeps the bare type
+		total, ok := column.Attributes[ColumnAttrTotal].(int)
+		if !ok {
+			return column.Type
+		}
 		var (
 			t      = column.Type
-			total  = strconv.Itoa(column.Attributes[ColumnAttrTotal].(int))
 			places = strconv.Itoa(column.Attributes[ColumnAttrPlaces].(int))
 		)
 		if t == ColumnTypeFloat {
 			t = ColumnTypeDouble
 		}
-		return t + "(" + total + ", " + places + ")"
+		return t + "(" + strconv.Itoa(total) + ", " + places + ")"
 
 	case ColumnTypeEnum:
 		return fmt.Sprintf("enum(%s)", g.quoteString(column.Attributes[ColumnAttrAllowed].([]string)))
@@ -65,24 +230,23 @@ func (g *Grammar) GetType(column *Column) string {
 
 	case ColumnTypeBoolean:
 		return ColumnTypeTinyInt + "(1)"
-	}
-
-	return ""
-}
 
-// quoteString Quote the given string literal.
-func (g *Grammar) quoteString(value []string) string {
-	value = arrMap(value, func(v string) string {
-		return "'" + v + "'"
-	})
+	case ColumnTypeGeometry, ColumnTypePoint, ColumnTypeLineString, ColumnTypePolygon,
+		ColumnTypeMultiPoint, ColumnTypeMultiPolygon, ColumnTypeGeometryCollection:
+		if srid, ok := column.srid(); ok {
+			return column.Type + " srid " + strconv.Itoa(srid)
+		}
+		return column.Type
+	}
 
-	return strings.Join(value, ", ")
+	// the other types, such as the inspected bit(1), are the raw column type
+	return column.Type
 }
 
 // addModifiers Add the column modifiers to the definition.
-func (g *Grammar) addModifiers(sql string, blueprint *Blueprint, column *Column) string {
+func (g *MysqlGrammar) addModifiers(sql string, blueprint *Blueprint, column *Column) string {
 	// modifiers := []string{
-	// 	"Unsigned", "Charset", "Collate", "Nullable", "Default", "Increment", "Comment",
+	// 	"Unsigned", "Charset", "Collate", "Generated", "Nullable", "Default", "Increment", "Comment", "After", "First",
 	// }
 
 	// Unsigned
@@ -97,9 +261,13 @@ func (g *Grammar) addModifiers(sql string, blueprint *Blueprint, column *Column)
 
 	// Collate
 	if collate, ok := column.Attributes[ColumnAttrCollate]; ok {
-		sql += " collate '" + collate.(string) + "'"
+		sql += " collate " + g.quote(collate.(string))
 	}
 
+	// Generated
+	generated := column.compileGenerated()
+	sql += generated
+
 	// Nullable
 	if nullable, ok := column.Attributes[ColumnAttrNullable]; ok && nullable.(bool) == true {
 		sql += " null"
@@ -107,42 +275,153 @@ func (g *Grammar) addModifiers(sql string, blueprint *Blueprint, column *Column)
 		sql += " not null"
 	}
 
-	// Default
-	if def, ok := column.Attributes[ColumnAttrDefault]; ok {
-		if def == "" {
-			sql += " default ''"
-		} else {
-			sql += " default '" + convString(def) + "'"
+	// a generated column has no default value and is not auto increment
+	if generated == "" {
+		// Default
+		if column.Attributes[ColumnAttrUseCurrent] == true {
+			sql += " default " + column.currentTimestamp()
+		} else if def, ok := column.Attributes[ColumnAttrDefault]; ok && def != nil {
+			sql += " default " + g.compileDefault(column)
 		}
-	}
 
-	// Increment
-	serials := []string{
-		ColumnTypeBigInt, ColumnTypeInt, ColumnTypeMediumInt, ColumnTypeSmallInt, ColumnTypeTinyInt,
-	}
-	if inArray(column.Type, serials) && column.Attributes[ColumnAttrAutoIncrement] == true {
-		sql += " auto_increment primary key"
+		// On update
+		if column.Attributes[ColumnAttrUseCurrentOnUpdate] == true {
+			sql += " on update " + column.currentTimestamp()
+		}
+
+		// Increment
+		serials := []string{
+			ColumnTypeBigInt, ColumnTypeInt, ColumnTypeMediumInt, ColumnTypeSmallInt, ColumnTypeTinyInt,
+		}
+		// the primary key of a changed column exists
+		if inArray(column.Type, serials) && column.Attributes[ColumnAttrAutoIncrement] == true {
+			sql += ternary(column.Attributes[ColumnAttrChange] == true, " auto_increment", " auto_increment primary key")
+		}
 	}
 
 	// Comment
 	if comment, ok := column.Attributes[ColumnAttrComment]; ok && comment.(string) != "" {
-		sql += " comment '" + comment.(string) + "'"
+		sql += " comment " + g.quote(comment.(string))
+	}
+
+	// Position
+	if after, ok := column.Attributes[ColumnAttrAfter].(string); ok {
+		sql += " after " + g.wrap(after)
+	} else if column.Attributes[ColumnAttrFirst] == true {
+		sql += " first"
 	}
 
 	return sql
 }
 
-func (g *Grammar) wrap(value string) string {
-	return "`" + value + "`"
+// validate check the default values
+func (g *MysqlGrammar) validate(blueprint *Blueprint) error {
+	return g.checkDefaults(blueprint)
+}
+
+// prepare check the reordered columns, and inspect the server version and the current columns
+// to rename columns before mysql 8.0
+func (g *MysqlGrammar) prepare(blueprint *Blueprint) (err error) {
+	if err = g.checkReorderColumns(blueprint); err != nil {
+		return err
+	}
+
+	if blueprint.creating() || blueprint.config.DB == nil || !blueprint.hasCommand(commandRenameColumn) {
+		return nil
+	}
+
+	err = blueprint.config.DB.QueryRowContext(blueprint.ctx, "select version()").Scan(&blueprint.version)
+	if err != nil || !g.legacyRename(blueprint.version) {
+		return err
+	}
+
+	if blueprint.currentColumns, err = blueprint.schema.GetColumns(blueprint.GetTable()); err != nil {
+		return err
+	}
+
+	for _, command := range blueprint.commands {
+		if from := command.Attributes[commandAttrFrom]; command.Name == commandRenameColumn && blueprint.currentColumn(from.(string)) == nil {
+			return fmt.Errorf("schema err: column %s.%s not found", blueprint.GetTable(), from)
+		}
+	}
+
+	return nil
 }
 
-func (g *Grammar) wrapTable(blueprint *Blueprint) string {
+// checkDefaults check the added and changed columns, mysql text, blob, json and spatial columns can not have
+// a literal default value, only an expression default, e.g. schema.Raw("(json_array())")
+func (g *MysqlGrammar) checkDefaults(blueprint *Blueprint) error {
+	types := []string{
+		ColumnTypeTinyText, ColumnTypeText, ColumnTypeMediumText, ColumnTypeLongText,
+		ColumnTypeTinyBlob, ColumnTypeBlob, ColumnTypeMediumBlob, ColumnTypeLongBlob, ColumnTypeJson,
+	}
+
+	for _, column := range blueprint.columns {
+		def := column.Attributes[ColumnAttrDefault]
+		if _, raw := def.(Expression); def != nil && !raw && (inArray(column.Type, types) || column.spatial()) {
+			return fmt.Errorf("schema err: %s column %s.%s can not have a default value", column.Type, blueprint.GetTable(), column.Name)
+		}
+	}
+
+	return nil
+}
+
+// checkReorderColumns check the reordered columns exist
+func (g *MysqlGrammar) checkReorderColumns(blueprint *Blueprint) error {
+	for _, command := range blueprint.commands {
+		if command.Name != commandReorderColumns || blueprint.currentColumns == nil {
+			continue
+		}
+
+		for _, column := range command.Attributes[commandAttrColumns].([]string) {
+			if blueprint.currentColumn(column) == nil {
+				return fmt.Errorf("schema err: column %s.%s not found", blueprint.GetTable(), column)
+			}
+		}
+	}
+
+	return nil
+}
+
+// legacyRename check the server can not rename column, mysql before 8.0 and mariadb
+func (g *MysqlGrammar) legacyRename(version string) bool {
+	if version == "" {
+		return false
+	}
+	return strings.Contains(strings.ToLower(version), "mariadb") || versionCompare(version, "8.0.0") < 0
+}
+
+// wrap Wrap the identifier in backticks, the backticks in the identifier are doubled.
+func (g *MysqlGrammar) wrap(value string) string {
+	return "`" + strings.Replace(value, "`", "``", -1) + "`"
+}
+
+// Wrap Wrap the identifier in backticks.
+func (g *MysqlGrammar) Wrap(value string) string {
+	return g.wrap(value)
+}
+
+// quote Quote the given string literal, mysql also escapes the backslashes.
+func (g *MysqlGrammar) quote(value string) string {
+	return g.baseGrammar.quote(strings.Replace(value, `\`, `\\`, -1))
+}
+
+// compileDefault Compile the default value of the column.
+func (g *MysqlGrammar) compileDefault(column *Column) string {
+	value, raw := column.defaultValue()
+	return ternary(raw, value, g.quote(value))
+}
+
+// quoteString Quote the given string literals.
+func (g *MysqlGrammar) quoteString(value []string) string {
+	return strings.Join(arrMap(value, g.quote), ", ")
+}
+
+func (g *MysqlGrammar) wrapTable(blueprint *Blueprint) string {
 	return g.wrap(blueprint.Prefix + blueprint.GetTable())
 }
 
-// prefixStrings
-func (g *Grammar) prefixStrings(prefix string, values []string) []string {
-	return arrMap(values, func(v string) string {
-		return prefix + v
-	})
+// Placeholder get the bind parameter placeholder
+func (g *MysqlGrammar) Placeholder(n int) string {
+	return "?"
 }
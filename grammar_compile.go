@@ -2,26 +2,16 @@ package schema
 
 import (
 	"fmt"
-	"reflect"
 	"strings"
 )
 
 // Compile compile command
-func (g *Grammar) Compile(blueprint *Blueprint, command *Command) string {
-	var (
-		value   = reflect.ValueOf(g)
-		name    = "Compile" + ucFirst(command.Name)
-		compile = value.MethodByName(name)
-	)
-
-	return compile.Call([]reflect.Value{
-		reflect.ValueOf(blueprint),
-		reflect.ValueOf(command),
-	})[0].Interface().(string)
+func (g *MysqlGrammar) Compile(blueprint *Blueprint, command *Command) ([]string, error) {
+	return compile(g, blueprint, command)
 }
 
 // CompileCreate Compile a create table command.
-func (g *Grammar) CompileCreate(blueprint *Blueprint, command *Command) string {
+func (g *MysqlGrammar) CompileCreate(blueprint *Blueprint, command *Command) string {
 	var sql string
 
 	sql = g.CompileCreateTable(blueprint)
@@ -31,16 +21,33 @@ func (g *Grammar) CompileCreate(blueprint *Blueprint, command *Command) string {
 	return sql
 }
 
-// CompileCreateTable Create the main create table clause.
-func (g *Grammar) CompileCreateTable(blueprint *Blueprint) string {
+// CompileCreateTable Create the main create table clause, with the check constraints.
+func (g *MysqlGrammar) CompileCreateTable(blueprint *Blueprint) string {
 	return trim(fmt.Sprintf(
 		"%s table %s (%s)", "create",
 		g.wrapTable(blueprint),
-		strings.Join(g.GetColumns(blueprint), ", ")))
+		strings.Join(append(g.GetColumns(blueprint), g.checkConstraints(blueprint)...), ", ")))
+}
+
+// checkConstraints Compile the check constraints of the check commands
+func (g *MysqlGrammar) checkConstraints(blueprint *Blueprint) (constraints []string) {
+	for _, command := range blueprint.commands {
+		if command.Name == commandCheck {
+			constraints = append(constraints, g.compileCheckConstraint(command))
+		}
+	}
+	return
+}
+
+// compileCheckConstraint Compile the check constraint of a check command
+func (g *MysqlGrammar) compileCheckConstraint(command *Command) string {
+	return fmt.Sprintf("constraint %s check (%s)",
+		g.wrap(command.Attributes[commandAttrIndex].(string)),
+		command.Attributes[commandAttrExpression].(string))
 }
 
 // CompileCreateEncoding Append the character set specifications to a command.
-func (g *Grammar) CompileCreateEncoding(sql string, blueprint *Blueprint) string {
+func (g *MysqlGrammar) CompileCreateEncoding(sql string, blueprint *Blueprint) string {
 	if blueprint.Charset != "" {
 		sql += " default character set " + blueprint.Charset
 	} else {
@@ -48,16 +55,16 @@ func (g *Grammar) CompileCreateEncoding(sql string, blueprint *Blueprint) string
 	}
 
 	if blueprint.Collation != "" {
-		sql += " collate '" + blueprint.Collation + "'"
+		sql += " collate " + g.quote(blueprint.Collation)
 	} else {
-		sql += " collate '" + DefaultCollation + "'"
+		sql += " collate " + g.quote(DefaultCollation)
 	}
 
 	return sql
 }
 
 // CompileCreateEngine Append the engine specifications to a command.
-func (g *Grammar) CompileCreateEngine(sql string, blueprint *Blueprint) string {
+func (g *MysqlGrammar) CompileCreateEngine(sql string, blueprint *Blueprint) string {
 	if blueprint.Engine != "" {
 		sql += " engine = " + blueprint.Engine
 	} else {
@@ -68,45 +75,135 @@ func (g *Grammar) CompileCreateEngine(sql string, blueprint *Blueprint) string {
 }
 
 // CompileAdd Compile an add column command.
-func (g *Grammar) CompileAdd(blueprint *Blueprint, command *Command) string {
+func (g *MysqlGrammar) CompileAdd(blueprint *Blueprint, command *Command) string {
 	columns := g.prefixStrings("add ", g.GetColumns(blueprint))
 	return "alter table " + g.wrapTable(blueprint) + " " + strings.Join(columns, ", ")
 }
 
 // CompileChange Compile a change column command into a series of SQL statements.
-func (g *Grammar) CompileChange(blueprint *Blueprint, command *Command) string {
+func (g *MysqlGrammar) CompileChange(blueprint *Blueprint, command *Command) string {
 	columns := g.prefixStrings("modify ", g.GetChangeColumns(blueprint))
 	return "alter table " + g.wrapTable(blueprint) + " " + strings.Join(columns, ", ")
 }
 
 // CompileRename Rename the table to given name
-func (g *Grammar) CompileRename(blueprint *Blueprint, command *Command) string {
+func (g *MysqlGrammar) CompileRename(blueprint *Blueprint, command *Command) string {
 	form := g.wrapTable(blueprint)
 	to := g.wrap(blueprint.Prefix + command.Attributes[commandAttrTo].(string))
 	return fmt.Sprintf("rename table %s to %s", form, to)
 }
 
-// func (g *Grammar) CompileRenameColumn(blueprint *Blueprint, command *Command) string {
-// }
+// CompileRenameColumn Compile a rename column command, mysql before 8.0 and mariadb
+// rename the column by change with the current column definition.
+func (g *MysqlGrammar) CompileRenameColumn(blueprint *Blueprint, command *Command) (string, error) {
+	var (
+		from = command.Attributes[commandAttrFrom].(string)
+		to   = command.Attributes[commandAttrTo].(string)
+	)
+
+	if g.legacyRename(blueprint.version) {
+		current := blueprint.currentColumn(from)
+		if current == nil {
+			return "", fmt.Errorf("schema err: column %s.%s not found", blueprint.GetTable(), from)
+		}
+
+		return fmt.Sprintf("alter table %s change %s %s", g.wrapTable(blueprint), g.wrap(from), g.currentDefinition(blueprint, to, current)), nil
+	}
+
+	return fmt.Sprintf("alter table %s rename column %s to %s", g.wrapTable(blueprint), g.wrap(from), g.wrap(to)), nil
+}
+
+// currentDefinition compile the definition of a current column with the column type reported by the database,
+// the type is kept verbatim, the modifiers are compiled from the column info
+func (g *MysqlGrammar) currentDefinition(blueprint *Blueprint, name string, info *ColumnInfo, modifiers ...func(column *Column)) string {
+	column := info.toColumn()
+	column.Name = name
+	column.Attributes[ColumnAttrChange] = true
+	// the unsigned is a part of the column type
+	delete(column.Attributes, ColumnAttrUnsigned)
+
+	for _, modifier := range modifiers {
+		modifier(column)
+	}
+
+	return g.addModifiers(g.wrap(name)+" "+info.Type, blueprint, column)
+}
+
+// CompileReorderColumns Compile a reorder columns command, the columns out of place are moved in order
+// by modify with the current column definitions, which are read from the database.
+func (g *MysqlGrammar) CompileReorderColumns(blueprint *Blueprint, command *Command) (statements []string, err error) {
+	if blueprint.currentColumns == nil {
+		return nil, fmt.Errorf("schema err: reorder the columns of %s requires the current columns, config.DB is nil", blueprint.GetTable())
+	}
+
+	var (
+		columns = command.Attributes[commandAttrColumns].([]string)
+		current []string
+	)
+
+	for _, column := range blueprint.currentColumns {
+		current = append(current, column.Name)
+	}
+
+	for i, name := range columns {
+		info := blueprint.currentColumn(name)
+		if info == nil || (i < len(current) && strings.EqualFold(current[i], info.Name)) {
+			continue
+		}
+
+		position := func(column *Column) {
+			if i == 0 {
+				column.First()
+			} else {
+				column.After(columns[i-1])
+			}
+		}
+
+		statements = append(statements, fmt.Sprintf(
+			"alter table %s modify %s",
+			g.wrapTable(blueprint),
+			g.currentDefinition(blueprint, info.Name, info, position)))
+
+		// move the column in the current order
+		current = filter(current, func(v string) bool { return v != info.Name })
+		current = append(current[:i], append([]string{info.Name}, current[i:]...)...)
+	}
+
+	return statements, nil
+}
 
 // CompilePrimary Compile a primary key command.
-func (g *Grammar) CompilePrimary(blueprint *Blueprint, command *Command) string {
-	command.Name = ""
+func (g *MysqlGrammar) CompilePrimary(blueprint *Blueprint, command *Command) string {
 	return g.CompileKey(blueprint, command, "primary key")
 }
 
 // CompileUnique Compile a unique key command.
-func (g *Grammar) CompileUnique(blueprint *Blueprint, command *Command) string {
+func (g *MysqlGrammar) CompileUnique(blueprint *Blueprint, command *Command) string {
 	return g.CompileKey(blueprint, command, "unique")
 }
 
 // CompileIndex Compile a plain index key command
-func (g *Grammar) CompileIndex(blueprint *Blueprint, command *Command) string {
+func (g *MysqlGrammar) CompileIndex(blueprint *Blueprint, command *Command) string {
 	return g.CompileKey(blueprint, command, "index")
 }
 
+// CompileSpatialIndex Compile a spatial index command
+func (g *MysqlGrammar) CompileSpatialIndex(blueprint *Blueprint, command *Command) string {
+	return g.CompileKey(blueprint, command, "spatial index")
+}
+
+// CompileFullText Compile a fulltext index command, e.g. with parser ngram
+func (g *MysqlGrammar) CompileFullText(blueprint *Blueprint, command *Command) string {
+	sql := g.CompileKey(blueprint, command, "fulltext index")
+
+	if parser, ok := command.Attributes[commandAttrParser].(string); ok && parser != "" {
+		sql += " with parser " + parser
+	}
+	return sql
+}
+
 // CompileKey Compile an index creation command.
-func (g *Grammar) CompileKey(blueprint *Blueprint, command *Command, types string) string {
+func (g *MysqlGrammar) CompileKey(blueprint *Blueprint, command *Command, types string) string {
 	var algorithm string
 
 	if algo, ok := command.Attributes[commandAttrAlgorithm]; ok && algo.(string) != "" {
@@ -120,23 +217,50 @@ func (g *Grammar) CompileKey(blueprint *Blueprint, command *Command, types strin
 		"alter table %s add %s %s%s(%s)",
 		g.wrapTable(blueprint),
 		types,
-		command.Attributes[commandIndex].(string),
+		g.wrap(command.Attributes[commandIndex].(string)),
 		algorithm,
 		columnize))
 }
 
+// CompileForeign Compile a foreign key command.
+func (g *MysqlGrammar) CompileForeign(blueprint *Blueprint, command *Command) string {
+	var (
+		columns    = arrMap(command.Attributes[commandAttrColumns].([]string), g.wrap)
+		references = arrMap(command.Attributes[commandAttrReferences].([]string), g.wrap)
+		on, _      = command.Attributes[commandAttrOn].(string)
+	)
+
+	sql := fmt.Sprintf(
+		"alter table %s add constraint %s foreign key (%s) references %s (%s)",
+		g.wrapTable(blueprint),
+		g.wrap(command.Attributes[commandAttrIndex].(string)),
+		strings.Join(columns, ", "),
+		g.wrap(blueprint.Prefix+on),
+		strings.Join(references, ", "))
+
+	if onDelete, ok := command.Attributes[commandAttrOnDelete]; ok {
+		sql += " on delete " + onDelete.(string)
+	}
+
+	if onUpdate, ok := command.Attributes[commandAttrOnUpdate]; ok {
+		sql += " on update " + onUpdate.(string)
+	}
+
+	return sql
+}
+
 // CompileDrop Compile a drop table command.
-func (g *Grammar) CompileDrop(blueprint *Blueprint, command *Command) string {
+func (g *MysqlGrammar) CompileDrop(blueprint *Blueprint, command *Command) string {
 	return "drop table " + g.wrapTable(blueprint)
 }
 
 // CompileDropIfExists Compile a drop table (if exists) command.
-func (g *Grammar) CompileDropIfExists(blueprint *Blueprint, command *Command) string {
+func (g *MysqlGrammar) CompileDropIfExists(blueprint *Blueprint, command *Command) string {
 	return "drop table if exists " + g.wrapTable(blueprint)
 }
 
 // CompileDropColumn Compile a drop column command.
-func (g *Grammar) CompileDropColumn(blueprint *Blueprint, command *Command) string {
+func (g *MysqlGrammar) CompileDropColumn(blueprint *Blueprint, command *Command) string {
 
 	if cols, ok := command.Attributes[commandAttrColumns]; ok {
 		var columns []string
@@ -152,32 +276,120 @@ func (g *Grammar) CompileDropColumn(blueprint *Blueprint, command *Command) stri
 }
 
 // CompileDropPrimary Compile a drop primary key command.
-func (g *Grammar) CompileDropPrimary(blueprint *Blueprint, command *Command) string {
+func (g *MysqlGrammar) CompileDropPrimary(blueprint *Blueprint, command *Command) string {
 	return "alter table " + g.wrapTable(blueprint) + " drop primary key"
 }
 
 // CompileDropUnique Compile a drop unique key command.
-func (g *Grammar) CompileDropUnique(blueprint *Blueprint, command *Command) string {
-	return "alter table " + g.wrapTable(blueprint) + " drop index" + command.Attributes[commandAttrIndex].(string)
+func (g *MysqlGrammar) CompileDropUnique(blueprint *Blueprint, command *Command) string {
+	return "alter table " + g.wrapTable(blueprint) + " drop index " + g.wrap(command.Attributes[commandAttrIndex].(string))
 }
 
 // CompileDropIndex Compile a drop index command.
-func (g *Grammar) CompileDropIndex(blueprint *Blueprint, command *Command) string {
-	return "alter table " + g.wrapTable(blueprint) + " drop index" + command.Attributes[commandAttrIndex].(string)
+func (g *MysqlGrammar) CompileDropIndex(blueprint *Blueprint, command *Command) string {
+	return "alter table " + g.wrapTable(blueprint) + " drop index " + g.wrap(command.Attributes[commandAttrIndex].(string))
+}
+
+// CompileDropFullText Compile a drop fulltext index command.
+func (g *MysqlGrammar) CompileDropFullText(blueprint *Blueprint, command *Command) string {
+	return g.CompileDropIndex(blueprint, command)
+}
+
+// CompileDropSpatialIndex Compile a drop spatial index command.
+func (g *MysqlGrammar) CompileDropSpatialIndex(blueprint *Blueprint, command *Command) string {
+	return g.CompileDropIndex(blueprint, command)
+}
+
+// CompileRenameIndex Compile a rename index command.
+func (g *MysqlGrammar) CompileRenameIndex(blueprint *Blueprint, command *Command) string {
+	return fmt.Sprintf(
+		"alter table %s rename index %s to %s",
+		g.wrapTable(blueprint),
+		g.wrap(command.Attributes[commandAttrFrom].(string)),
+		g.wrap(command.Attributes[commandAttrTo].(string)))
+}
+
+// CompileCheck Compile a check constraint command, the constraints of a created table are in the create statement.
+func (g *MysqlGrammar) CompileCheck(blueprint *Blueprint, command *Command) string {
+	if blueprint.creating() {
+		return ""
+	}
+	return "alter table " + g.wrapTable(blueprint) + " add " + g.compileCheckConstraint(command)
+}
+
+// CompileDropCheck Compile a drop check constraint command, mysql 8.0.19+.
+func (g *MysqlGrammar) CompileDropCheck(blueprint *Blueprint, command *Command) string {
+	return "alter table " + g.wrapTable(blueprint) + " drop check " + g.wrap(command.Attributes[commandAttrIndex].(string))
+}
+
+// CompileDropForeign Compile a drop foreign key command.
+func (g *MysqlGrammar) CompileDropForeign(blueprint *Blueprint, command *Command) string {
+	index := g.wrap(command.Attributes[commandAttrIndex].(string))
+	return "alter table " + g.wrapTable(blueprint) + " drop foreign key " + index
 }
 
 // CompileTableComment Compile a table comment command.
-func (g *Grammar) CompileTableComment(blueprint *Blueprint, command *Command) string {
+func (g *MysqlGrammar) CompileTableComment(blueprint *Blueprint, command *Command) string {
 	comment := command.Attributes[commandAttrComment].(string)
 
 	return fmt.Sprintf(
 		"alter table %s comment = %s",
 		g.wrapTable(blueprint),
-		"'"+strings.Replace(comment, "'", "''", -1)+"'",
+		g.quote(comment),
 	)
 }
 
 // CompileTableExists Compile the query to determine the list of tables
-func (g *Grammar) CompileTableExists() string {
-	return "select * from information_schema.tables where table_schema = ? and table_name = ? and table_type = 'BASE TABLE'"
+func (g *MysqlGrammar) CompileTableExists(database, table string) (string, []interface{}) {
+	return "select * from information_schema.tables where table_schema = ? and table_name = ? and table_type = 'BASE TABLE'",
+		[]interface{}{database, table}
+}
+
+// CompileTables Compile the query to get the tables of a database
+func (g *MysqlGrammar) CompileTables(database string) (string, []interface{}) {
+	return "select table_name as `name`, table_comment as `comment` from information_schema.tables " +
+			"where table_schema = ? and table_type = 'BASE TABLE' order by table_name",
+		[]interface{}{database}
+}
+
+// CompileColumns Compile the query to get the columns of a table
+func (g *MysqlGrammar) CompileColumns(database, table string) (string, []interface{}) {
+	return "select column_name as `name`, data_type as `type_name`, column_type as `type`, " +
+			"character_maximum_length as `length`, is_nullable = 'YES' as `nullable`, column_default as `default`, " +
+			"character_set_name as `charset`, collation_name as `collation`, column_comment as `comment`, " +
+			"extra like '%auto_increment%' as `auto_increment`, ordinal_position as `position`, " +
+			"case when extra like '%on update %' then substring(extra, locate('on update ', extra) + 10) end as `on_update`, " +
+			"case when extra like '%virtual generated%' then 'virtual' when extra like '%stored generated%' or extra like '%persistent generated%' then 'stored' end as `generated`, " +
+			"nullif(generation_expression, '') as `expression` " +
+			"from information_schema.columns where table_schema = ? and table_name = ? order by ordinal_position",
+		[]interface{}{database, table}
+}
+
+// CompileIndexes Compile the query to get the indexes of a table
+func (g *MysqlGrammar) CompileIndexes(database, table string) (string, []interface{}) {
+	return "select index_name as `name`, column_name as `column`, index_type as `type`, non_unique = 0 as `unique`, " +
+			"index_name = 'PRIMARY' as `primary`, seq_in_index as `position` " +
+			"from information_schema.statistics where table_schema = ? and table_name = ? order by index_name, seq_in_index",
+		[]interface{}{database, table}
+}
+
+// CompileForeignKeys Compile the query to get the foreign keys of a table
+func (g *MysqlGrammar) CompileForeignKeys(database, table string) (string, []interface{}) {
+	return "select kc.constraint_name as `name`, kc.column_name as `column`, kc.referenced_table_name as `foreign_table`, " +
+			"kc.referenced_column_name as `foreign_column`, rc.update_rule as `on_update`, rc.delete_rule as `on_delete`, " +
+			"kc.ordinal_position as `position` " +
+			"from information_schema.key_column_usage kc join information_schema.referential_constraints rc " +
+			"on rc.constraint_schema = kc.table_schema and rc.constraint_name = kc.constraint_name " +
+			"where kc.table_schema = ? and kc.table_name = ? and kc.referenced_table_name is not null " +
+			"order by kc.constraint_name, kc.ordinal_position",
+		[]interface{}{database, table}
+}
+
+// CompileChecks Compile the query to get the check constraints of a table, mysql 8.0.16+ and mariadb 10.2+
+func (g *MysqlGrammar) CompileChecks(database, table string) (string, []interface{}) {
+	return "select cc.constraint_name as `name`, cc.check_clause as `expression` " +
+			"from information_schema.table_constraints tc join information_schema.check_constraints cc " +
+			"on cc.constraint_schema = tc.constraint_schema and cc.constraint_name = tc.constraint_name " +
+			"where tc.table_schema = ? and tc.table_name = ? and tc.constraint_type = 'CHECK' order by cc.constraint_name",
+		[]interface{}{database, table}
 }
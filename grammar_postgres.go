@@ -0,0 +1,523 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PostgresGrammar postgresql grammar
+type PostgresGrammar struct {
+	baseGrammar
+}
+
+// Compile compile command
+func (g *PostgresGrammar) Compile(blueprint *Blueprint, command *Command) ([]string, error) {
+	return compile(g, blueprint, command)
+}
+
+// GetColumns get add columns
+func (g *PostgresGrammar) GetColumns(blueprint *Blueprint) []string {
+	var columns []string
+
+	for _, column := range blueprint.getAddedColumns() {
+		sql := g.wrap(column.Name) + " " + g.GetType(column)
+		columns = append(columns, g.addModifiers(sql, blueprint, column))
+	}
+
+	return columns
+}
+
+// GetChangeColumns get change columns, every column is changed by a list of alter column clauses
+func (g *PostgresGrammar) GetChangeColumns(blueprint *Blueprint) (columns []string) {
+	for _, column := range blueprint.getChangedColumns() {
+		var (
+			name    = "alter column " + g.wrap(column.Name)
+			changes = []string{name + " type " + g.GetType(column)}
+		)
+
+		if collate, ok := column.Attributes[ColumnAttrCollate]; ok {
+			changes[0] += " collate " + g.wrap(collate.(string))
+		}
+
+		if nullable, ok := column.Attributes[ColumnAttrNullable]; ok && nullable.(bool) == true {
+			changes = append(changes, name+" drop not null")
+		} else {
+			changes = append(changes, name+" set not null")
+		}
+
+		if expression, storage := column.generated(); storage != "" {
+			changes = append(changes, name+" set expression as ("+expression+")")
+		} else if column.Attributes[ColumnAttrUseCurrent] == true {
+			changes = append(changes, name+" set default "+column.currentTimestamp())
+		} else if def, ok := column.Attributes[ColumnAttrDefault]; ok && def != nil {
+			changes = append(changes, name+" set default "+g.compileDefault(column))
+		} else {
+			changes = append(changes, name+" drop default")
+		}
+
+		columns = append(columns, strings.Join(changes, ", "))
+	}
+	return
+}
+
+// GetType get column type
+func (g *PostgresGrammar) GetType(column *Column) string {
+	switch column.Type {
+	case ColumnTypeChar, ColumnTypeVarchar:
+		return column.Type + "(" + strconv.Itoa(column.Attributes[ColumnAttrLength].(int)) + ")"
+
+	case ColumnTypeTinyText, ColumnTypeText, ColumnTypeMediumText, ColumnTypeLongText:
+		return "text"
+
+	case ColumnTypeBigInt:
+		return ternary(g.serial(column), "bigserial", "bigint")
+
+	case ColumnTypeInt, ColumnTypeMediumInt:
+		return ternary(g.serial(column), "serial", "integer")
+
+	case ColumnTypeTinyInt, ColumnTypeSmallInt:
+		return ternary(g.serial(column), "smallserial", "smallint")
+
+	case ColumnTypeBoolean:
+		return "boolean"
+
+	case ColumnTypeFloat, ColumnTypeDouble:
+		return "double precision"
+
+	case ColumnTypeDecimal:
+		total, ok := column.Attributes[ColumnAttrTotal].(int)
+		if !ok {
+			return "decimal"
+		}
+		return "decimal(" + strconv.Itoa(total) + ", " + strconv.Itoa(column.Attributes[ColumnAttrPlaces].(int)) + ")"
+
+	case ColumnTypeEnum:
+		if column.Attributes[ColumnAttrChange] == true {
+			return "varchar(255)"
+		}
+		return fmt.Sprintf("varchar(255) check (%s in (%s))",
+			g.wrap(column.Name), g.quoteString(column.Attributes[ColumnAttrAllowed].([]string)))
+
+	case ColumnTypeSet:
+		return "varchar(255)"
+
+	case ColumnTypeJson:
+		return "jsonb"
+
+	case ColumnTypeDate:
+		return "date"
+
+	case ColumnTypeDateTime, ColumnTypeTimestamp:
+		return fmt.Sprintf("timestamp(%d) without time zone", column.precision())
+
+	case ColumnTypeTime:
+		return fmt.Sprintf("time(%d) without time zone", column.precision())
+
+	case ColumnTypeYear:
+		return "integer"
+
+	case ColumnTypeBinary, ColumnTypeVarBinary, ColumnTypeTinyBlob, ColumnTypeBlob, ColumnTypeMediumBlob, ColumnTypeLongBlob:
+		return "bytea"
+
+	case ColumnTypeUuid:
+		return "uuid"
+
+	case ColumnTypeGeometry, ColumnTypePoint, ColumnTypeLineString, ColumnTypePolygon,
+		ColumnTypeMultiPoint, ColumnTypeMultiPolygon, ColumnTypeGeometryCollection:
+		srid, ok := column.srid()
+		switch {
+		case ok:
+			return fmt.Sprintf("geometry(%s, %d)", column.Type, srid)
+		case column.Type != ColumnTypeGeometry:
+			return "geometry(" + column.Type + ")"
+		}
+		return "geometry"
+	}
+
+	return column.Type
+}
+
+// serial check the column should use a serial type
+func (g *PostgresGrammar) serial(column *Column) bool {
+	return column.Attributes[ColumnAttrAutoIncrement] == true && column.Attributes[ColumnAttrChange] != true
+}
+
+// addModifiers Add the column modifiers to the definition.
+func (g *PostgresGrammar) addModifiers(sql string, blueprint *Blueprint, column *Column) string {
+	// Collate
+	if collate, ok := column.Attributes[ColumnAttrCollate]; ok {
+		sql += " collate " + g.wrap(collate.(string))
+	}
+
+	// Generated, postgres before 18 only supports stored generated columns
+	generated := column.compileGenerated()
+	sql += generated
+
+	// Nullable
+	if nullable, ok := column.Attributes[ColumnAttrNullable]; ok && nullable.(bool) == true {
+		sql += " null"
+	} else {
+		sql += " not null"
+	}
+
+	// Default, a generated column has no default value
+	switch def, ok := column.Attributes[ColumnAttrDefault]; {
+	case generated != "":
+	case column.Attributes[ColumnAttrUseCurrent] == true:
+		sql += " default " + column.currentTimestamp()
+	case ok && def != nil:
+		sql += " default " + g.compileDefault(column)
+	}
+
+	// Increment
+	if g.serial(column) {
+		sql += " primary key"
+	}
+
+	return sql
+}
+
+// Placeholder get the bind parameter placeholder
+func (g *PostgresGrammar) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// wrap Wrap the identifier in double quotes, the double quotes in the identifier are doubled.
+func (g *PostgresGrammar) wrap(value string) string {
+	return `"` + strings.Replace(value, `"`, `""`, -1) + `"`
+}
+
+// Wrap Wrap the identifier in double quotes.
+func (g *PostgresGrammar) Wrap(value string) string {
+	return g.wrap(value)
+}
+
+func (g *PostgresGrammar) wrapTable(blueprint *Blueprint) string {
+	return g.wrap(blueprint.Prefix + blueprint.GetTable())
+}
+
+// compileDefault Compile the default value of the column, the bools of boolean columns are true or false.
+func (g *PostgresGrammar) compileDefault(column *Column) string {
+	value, raw := column.defaultValue()
+	if raw && column.Type == ColumnTypeBoolean && (value == "1" || value == "0") {
+		return ternary(value == "1", "true", "false")
+	}
+	return ternary(raw, value, g.quote(value))
+}
+
+// quoteComment Quote the given comment literal.
+func (g *PostgresGrammar) quoteComment(comment string) string {
+	if comment == "" {
+		return "NULL"
+	}
+	return g.quote(comment)
+}
+
+// compileColumnComments Compile the comment statements of the given columns,
+// postgres does not support inline column comments.
+func (g *PostgresGrammar) compileColumnComments(blueprint *Blueprint, columns []*Column) (statements []string) {
+	for _, column := range columns {
+		comment, ok := column.Attributes[ColumnAttrComment]
+		if !ok || (comment.(string) == "" && column.Attributes[ColumnAttrChange] != true) {
+			continue
+		}
+
+		statements = append(statements, fmt.Sprintf(
+			"comment on column %s.%s is %s",
+			g.wrapTable(blueprint),
+			g.wrap(column.Name),
+			g.quoteComment(comment.(string)),
+		))
+	}
+	return
+}
+
+// CompileCreate Compile a create table command.
+func (g *PostgresGrammar) CompileCreate(blueprint *Blueprint, command *Command) []string {
+	var columns = g.GetColumns(blueprint)
+
+	for _, c := range blueprint.commands {
+		if c.Name == commandCheck {
+			columns = append(columns, g.compileCheckConstraint(c))
+		}
+	}
+
+	sql := fmt.Sprintf("create table %s (%s)", g.wrapTable(blueprint), strings.Join(columns, ", "))
+
+	return append([]string{sql}, g.compileColumnComments(blueprint, blueprint.getAddedColumns())...)
+}
+
+// CompileAdd Compile an add column command.
+func (g *PostgresGrammar) CompileAdd(blueprint *Blueprint, command *Command) []string {
+	columns := g.prefixStrings("add column ", g.GetColumns(blueprint))
+	sql := "alter table " + g.wrapTable(blueprint) + " " + strings.Join(columns, ", ")
+
+	return append([]string{sql}, g.compileColumnComments(blueprint, blueprint.getAddedColumns())...)
+}
+
+// CompileChange Compile a change column command into a series of SQL statements.
+func (g *PostgresGrammar) CompileChange(blueprint *Blueprint, command *Command) []string {
+	sql := "alter table " + g.wrapTable(blueprint) + " " + strings.Join(g.GetChangeColumns(blueprint), ", ")
+
+	return append([]string{sql}, g.compileColumnComments(blueprint, blueprint.getChangedColumns())...)
+}
+
+// CompileRename Rename the table to given name
+func (g *PostgresGrammar) CompileRename(blueprint *Blueprint, command *Command) string {
+	to := g.wrap(blueprint.Prefix + command.Attributes[commandAttrTo].(string))
+	return fmt.Sprintf("alter table %s rename to %s", g.wrapTable(blueprint), to)
+}
+
+// CompileRenameColumn Compile a rename column command.
+func (g *PostgresGrammar) CompileRenameColumn(blueprint *Blueprint, command *Command) string {
+	return fmt.Sprintf(
+		"alter table %s rename column %s to %s",
+		g.wrapTable(blueprint),
+		g.wrap(command.Attributes[commandAttrFrom].(string)),
+		g.wrap(command.Attributes[commandAttrTo].(string)))
+}
+
+// CompilePrimary Compile a primary key command.
+func (g *PostgresGrammar) CompilePrimary(blueprint *Blueprint, command *Command) string {
+	return fmt.Sprintf("alter table %s add primary key (%s)", g.wrapTable(blueprint), g.columnize(command))
+}
+
+// CompileUnique Compile a unique key command.
+func (g *PostgresGrammar) CompileUnique(blueprint *Blueprint, command *Command) string {
+	return fmt.Sprintf(
+		"alter table %s add constraint %s unique (%s)",
+		g.wrapTable(blueprint),
+		g.wrap(command.Attributes[commandAttrIndex].(string)),
+		g.columnize(command))
+}
+
+// CompileIndex Compile a plain index key command
+func (g *PostgresGrammar) CompileIndex(blueprint *Blueprint, command *Command) string {
+	var algorithm string
+
+	if algo, ok := command.Attributes[commandAttrAlgorithm]; ok && algo.(string) != "" {
+		algorithm = " using " + algo.(string)
+	}
+
+	return fmt.Sprintf(
+		"create index %s on %s%s (%s)",
+		g.wrap(command.Attributes[commandAttrIndex].(string)),
+		g.wrapTable(blueprint),
+		algorithm,
+		g.columnize(command))
+}
+
+// CompileSpatialIndex Compile a spatial index command by a gist index
+func (g *PostgresGrammar) CompileSpatialIndex(blueprint *Blueprint, command *Command) string {
+	return fmt.Sprintf(
+		"create index %s on %s using gist (%s)",
+		g.wrap(command.Attributes[commandAttrIndex].(string)),
+		g.wrapTable(blueprint),
+		g.columnize(command))
+}
+
+// CompileFullText Compile a fulltext index command by a gin index of the simple text search configuration,
+// the parser is ignored.
+func (g *PostgresGrammar) CompileFullText(blueprint *Blueprint, command *Command) string {
+	vectors := arrMap(command.Attributes[commandAttrColumns].([]string), func(column string) string {
+		return "to_tsvector('simple', " + g.wrap(column) + ")"
+	})
+
+	return fmt.Sprintf(
+		"create index %s on %s using gin ((%s))",
+		g.wrap(command.Attributes[commandAttrIndex].(string)),
+		g.wrapTable(blueprint),
+		strings.Join(vectors, " || "))
+}
+
+// columnize wrap and join the columns of command
+func (g *PostgresGrammar) columnize(command *Command) string {
+	return strings.Join(arrMap(command.Attributes[commandAttrColumns].([]string), g.wrap), ", ")
+}
+
+// CompileForeign Compile a foreign key command.
+func (g *PostgresGrammar) CompileForeign(blueprint *Blueprint, command *Command) string {
+	return fmt.Sprintf(
+		"alter table %s add constraint %s %s",
+		g.wrapTable(blueprint),
+		g.wrap(command.Attributes[commandAttrIndex].(string)),
+		g.compileForeignKey(blueprint, command))
+}
+
+// compileForeignKey Compile the foreign key clause of a foreign command
+func (g *PostgresGrammar) compileForeignKey(blueprint *Blueprint, command *Command) string {
+	on, _ := command.Attributes[commandAttrOn].(string)
+
+	sql := fmt.Sprintf(
+		"foreign key (%s) references %s (%s)",
+		g.columnize(command),
+		g.wrap(blueprint.Prefix+on),
+		strings.Join(arrMap(command.Attributes[commandAttrReferences].([]string), g.wrap), ", "))
+
+	if onDelete, ok := command.Attributes[commandAttrOnDelete]; ok {
+		sql += " on delete " + onDelete.(string)
+	}
+
+	if onUpdate, ok := command.Attributes[commandAttrOnUpdate]; ok {
+		sql += " on update " + onUpdate.(string)
+	}
+
+	return sql
+}
+
+// CompileDrop Compile a drop table command.
+func (g *PostgresGrammar) CompileDrop(blueprint *Blueprint, command *Command) string {
+	return "drop table " + g.wrapTable(blueprint)
+}
+
+// CompileDropIfExists Compile a drop table (if exists) command.
+func (g *PostgresGrammar) CompileDropIfExists(blueprint *Blueprint, command *Command) string {
+	return "drop table if exists " + g.wrapTable(blueprint)
+}
+
+// CompileDropColumn Compile a drop column command.
+func (g *PostgresGrammar) CompileDropColumn(blueprint *Blueprint, command *Command) string {
+	if cols, ok := command.Attributes[commandAttrColumns]; ok {
+		columns := g.prefixStrings("drop column ", arrMap(cols.([]string), g.wrap))
+		return "alter table " + g.wrapTable(blueprint) + " " + strings.Join(columns, ", ")
+	}
+
+	return ""
+}
+
+// CompileDropPrimary Compile a drop primary key command.
+func (g *PostgresGrammar) CompileDropPrimary(blueprint *Blueprint, command *Command) string {
+	index := g.wrap(blueprint.Prefix + blueprint.GetTable() + "_pkey")
+	return "alter table " + g.wrapTable(blueprint) + " drop constraint " + index
+}
+
+// CompileDropUnique Compile a drop unique key command.
+func (g *PostgresGrammar) CompileDropUnique(blueprint *Blueprint, command *Command) string {
+	index := g.wrap(command.Attributes[commandAttrIndex].(string))
+	return "alter table " + g.wrapTable(blueprint) + " drop constraint " + index
+}
+
+// CompileDropIndex Compile a drop index command.
+func (g *PostgresGrammar) CompileDropIndex(blueprint *Blueprint, command *Command) string {
+	return "drop index " + g.wrap(command.Attributes[commandAttrIndex].(string))
+}
+
+// CompileDropFullText Compile a drop fulltext index command.
+func (g *PostgresGrammar) CompileDropFullText(blueprint *Blueprint, command *Command) string {
+	return g.CompileDropIndex(blueprint, command)
+}
+
+// CompileDropSpatialIndex Compile a drop spatial index command.
+func (g *PostgresGrammar) CompileDropSpatialIndex(blueprint *Blueprint, command *Command) string {
+	return g.CompileDropIndex(blueprint, command)
+}
+
+// CompileRenameIndex Compile a rename index command.
+func (g *PostgresGrammar) CompileRenameIndex(blueprint *Blueprint, command *Command) string {
+	return fmt.Sprintf(
+		"alter index %s rename to %s",
+		g.wrap(command.Attributes[commandAttrFrom].(string)),
+		g.wrap(command.Attributes[commandAttrTo].(string)))
+}
+
+// CompileCheck Compile a check constraint command, the constraints of a created table are in the create statement.
+func (g *PostgresGrammar) CompileCheck(blueprint *Blueprint, command *Command) string {
+	if blueprint.creating() {
+		return ""
+	}
+	return "alter table " + g.wrapTable(blueprint) + " add " + g.compileCheckConstraint(command)
+}
+
+// CompileDropCheck Compile a drop check constraint command.
+func (g *PostgresGrammar) CompileDropCheck(blueprint *Blueprint, command *Command) string {
+	return "alter table " + g.wrapTable(blueprint) + " drop constraint " + g.wrap(command.Attributes[commandAttrIndex].(string))
+}
+
+// compileCheckConstraint Compile the check constraint of a check command
+func (g *PostgresGrammar) compileCheckConstraint(command *Command) string {
+	return fmt.Sprintf("constraint %s check (%s)",
+		g.wrap(command.Attributes[commandAttrIndex].(string)),
+		command.Attributes[commandAttrExpression].(string))
+}
+
+// CompileDropForeign Compile a drop foreign key command.
+func (g *PostgresGrammar) CompileDropForeign(blueprint *Blueprint, command *Command) string {
+	index := g.wrap(command.Attributes[commandAttrIndex].(string))
+	return "alter table " + g.wrapTable(blueprint) + " drop constraint " + index
+}
+
+// CompileTableComment Compile a table comment command.
+func (g *PostgresGrammar) CompileTableComment(blueprint *Blueprint, command *Command) string {
+	comment := command.Attributes[commandAttrComment].(string)
+	return fmt.Sprintf("comment on table %s is %s", g.wrapTable(blueprint), g.quoteComment(comment))
+}
+
+// CompileTableExists Compile the query to determine if a table exists
+func (g *PostgresGrammar) CompileTableExists(database, table string) (string, []interface{}) {
+	return "select * from information_schema.tables where table_catalog = $1 and table_schema = current_schema() and table_name = $2 and table_type = 'BASE TABLE'",
+		[]interface{}{database, table}
+}
+
+// CompileTables Compile the query to get the tables of a database
+func (g *PostgresGrammar) CompileTables(database string) (string, []interface{}) {
+	return "select c.relname as name, obj_description(c.oid, 'pg_class') as comment " +
+			"from pg_class c join pg_namespace n on n.oid = c.relnamespace " +
+			"where current_database() = $1 and n.nspname = current_schema() and c.relkind in ('r', 'p') order by c.relname",
+		[]interface{}{database}
+}
+
+// CompileColumns Compile the query to get the columns of a table
+func (g *PostgresGrammar) CompileColumns(database, table string) (string, []interface{}) {
+	return "select c.column_name as name, c.udt_name as type_name, format_type(a.atttypid, a.atttypmod) as type, " +
+			"c.character_maximum_length as length, c.is_nullable = 'YES' as nullable, c.column_default as \"default\", " +
+			"c.character_set_name as charset, c.collation_name as collation, col_description(a.attrelid, a.attnum) as comment, " +
+			"coalesce(c.column_default like 'nextval(%', false) or c.is_identity = 'YES' as auto_increment, c.ordinal_position as position, " +
+			"null as on_update, case a.attgenerated when 's' then 'stored' when 'v' then 'virtual' end as generated, " +
+			"c.generation_expression as expression " +
+			"from information_schema.columns c join pg_catalog.pg_attribute a " +
+			"on a.attrelid = (quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::regclass and a.attname = c.column_name " +
+			"where c.table_catalog = $1 and c.table_schema = current_schema() and c.table_name = $2 order by c.ordinal_position",
+		[]interface{}{database, table}
+}
+
+// CompileIndexes Compile the query to get the indexes of a table
+func (g *PostgresGrammar) CompileIndexes(database, table string) (string, []interface{}) {
+	return "select ic.relname as name, a.attname as \"column\", am.amname as type, i.indisunique as \"unique\", " +
+			"i.indisprimary as \"primary\", k.ordinality as position " +
+			"from pg_index i join pg_class tc on tc.oid = i.indrelid join pg_class ic on ic.oid = i.indexrelid " +
+			"join pg_am am on am.oid = ic.relam join pg_namespace n on n.oid = tc.relnamespace " +
+			"join lateral unnest(i.indkey) with ordinality as k(attnum, ordinality) on true " +
+			"join pg_attribute a on a.attrelid = tc.oid and a.attnum = k.attnum " +
+			"where current_database() = $1 and n.nspname = current_schema() and tc.relname = $2 " +
+			"order by ic.relname, k.ordinality",
+		[]interface{}{database, table}
+}
+
+// CompileForeignKeys Compile the query to get the foreign keys of a table
+func (g *PostgresGrammar) CompileForeignKeys(database, table string) (string, []interface{}) {
+	rule := func(column string) string {
+		return "case " + column + " when 'a' then 'no action' when 'r' then 'restrict' when 'c' then 'cascade' " +
+			"when 'n' then 'set null' when 'd' then 'set default' end"
+	}
+
+	return "select c.conname as name, a.attname as \"column\", fc.relname as foreign_table, fa.attname as foreign_column, " +
+			rule("c.confupdtype") + " as on_update, " + rule("c.confdeltype") + " as on_delete, k.ordinality as position " +
+			"from pg_constraint c join pg_class tc on tc.oid = c.conrelid join pg_namespace n on n.oid = tc.relnamespace " +
+			"join pg_class fc on fc.oid = c.confrelid " +
+			"join lateral unnest(c.conkey, c.confkey) with ordinality as k(attnum, fattnum, ordinality) on true " +
+			"join pg_attribute a on a.attrelid = c.conrelid and a.attnum = k.attnum " +
+			"join pg_attribute fa on fa.attrelid = c.confrelid and fa.attnum = k.fattnum " +
+			"where c.contype = 'f' and current_database() = $1 and n.nspname = current_schema() and tc.relname = $2 " +
+			"order by c.conname, k.ordinality",
+		[]interface{}{database, table}
+}
+
+// CompileChecks Compile the query to get the check constraints of a table
+func (g *PostgresGrammar) CompileChecks(database, table string) (string, []interface{}) {
+	return "select c.conname as name, pg_get_expr(c.conbin, c.conrelid) as expression " +
+			"from pg_constraint c join pg_class tc on tc.oid = c.conrelid join pg_namespace n on n.oid = tc.relnamespace " +
+			"where c.contype = 'c' and current_database() = $1 and n.nspname = current_schema() and tc.relname = $2 " +
+			"order by c.conname",
+		[]interface{}{database, table}
+}
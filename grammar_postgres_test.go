@@ -0,0 +1,281 @@
+package schema
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPostgresGrammar_ToSql(t *testing.T) {
+	type sqlCase struct {
+		name     string
+		table    string
+		sql      []string
+		callback func(table *Blueprint)
+	}
+	cases := []sqlCase{
+		{
+			name:  "Id",
+			table: "users",
+			sql: []string{
+				`create table "users" ("id" bigserial not null primary key, "age" serial not null primary key)`,
+			},
+			callback: func(table *Blueprint) {
+				table.create()
+				table.Id("id")
+				table.Increments("age")
+			},
+		},
+		{
+			name:  "Types",
+			table: "users",
+			sql: []string{
+				`create table "users" ("name" varchar(20) not null, "bio" text null, "age" integer not null, "flag" boolean not null, "price" decimal(10, 2) not null, "data" jsonb not null, "uuid" uuid not null, "created_at" timestamp(0) without time zone null, "file" bytea not null)`,
+			},
+			callback: func(table *Blueprint) {
+				table.create()
+				table.String("name", 20)
+				table.Text("bio").Nullable()
+				table.UnsignedInt("age")
+				table.Boolean("flag")
+				table.Decimal("price", 10, 2)
+				table.Json("data")
+				table.Uuid("uuid")
+				table.Timestamp("created_at").Nullable()
+				table.Binary("file")
+			},
+		},
+		{
+			name:  "Enum",
+			table: "users",
+			sql: []string{
+				`create table "users" ("type" varchar(255) check ("type" in ('one', 'two')) not null default 'one')`,
+			},
+			callback: func(table *Blueprint) {
+				table.create()
+				table.Enum("type", []string{"one", "two"}).Default("one")
+			},
+		},
+		{
+			name:  "Comment",
+			table: "users",
+			sql: []string{
+				`create table "users" ("name" varchar(30) not null)`,
+				`comment on column "users"."name" is '姓名'`,
+				`comment on table "users" is 'user''s table'`,
+			},
+			callback: func(table *Blueprint) {
+				table.create()
+				table.String("name", 30).Comment("姓名")
+				table.Comment("user's table")
+			},
+		},
+		{
+			name:  "Add_Change_Drop",
+			table: "users",
+			sql: []string{
+				`alter table "users" drop column "age", drop column "account"`,
+				`alter table "users" add column "email" varchar(50) null`,
+				`alter table "users" alter column "name" type varchar(30), alter column "name" set not null, alter column "name" set default 'a'`,
+			},
+			callback: func(table *Blueprint) {
+				table.DropColumn("age", "account")
+				table.String("email", 50).Nullable()
+				table.String("name", 30).Default("a").Change()
+			},
+		},
+		{
+			name:  "Index",
+			table: "users",
+			sql: []string{
+				`create table "users" ("id" integer not null, "account" varchar(50) not null, "age" integer not null)`,
+				`alter table "users" add constraint "users_account_unique" unique ("account")`,
+				`alter table "users" add primary key ("id")`,
+				`create index "users_age_index" on "users" ("age")`,
+			},
+			callback: func(table *Blueprint) {
+				table.create()
+				table.UnsignedInt("id").Primary()
+				table.String("account", 50)
+				table.Int("age").Index()
+
+				table.Unique("account")
+			},
+		},
+		{
+			name:  "Rename",
+			table: "users",
+			sql: []string{
+				`alter table "users" rename to "new_users"`,
+			},
+			callback: func(table *Blueprint) {
+				table.Rename("new_users")
+			},
+		},
+		{
+			name:  "Drop_Indexes",
+			table: "users",
+			sql: []string{
+				`alter table "users" drop constraint "users_pkey"`,
+				`alter table "users" drop constraint "users_account_unique"`,
+				`drop index "users_age_index"`,
+				`drop index "users_bio_fulltext"`,
+				`alter index "users_name_index" rename to "users_nickname_index"`,
+			},
+			callback: func(table *Blueprint) {
+				table.DropPrimary()
+				table.DropUnique([]string{"account"})
+				table.DropIndex([]string{"age"})
+				table.DropFulltext("users_bio_fulltext")
+				table.RenameIndex("users_name_index", "users_nickname_index")
+			},
+		},
+		{
+			name:  "RenameColumn",
+			table: "users",
+			sql: []string{
+				`alter table "users" rename column "name" to "nickname"`,
+			},
+			callback: func(table *Blueprint) {
+				table.RenameColumn("name", "nickname")
+			},
+		},
+		{
+			name:  "Foreign",
+			table: "posts",
+			sql: []string{
+				`alter table "posts" add constraint "posts_user_id_foreign" foreign key ("user_id") references "users" ("id") on delete cascade`,
+				`alter table "posts" drop constraint "posts_author_id_foreign"`,
+			},
+			callback: func(table *Blueprint) {
+				table.Foreign("user_id").References("id").On("users").CascadeOnDelete()
+				table.DropForeign([]string{"author_id"})
+			},
+		},
+		{
+			name:  "Default_Types",
+			table: "users",
+			sql: []string{
+				`alter table "users" add column "active" boolean not null default true, add column "age" integer not null default 18, add column "created_at" timestamp(0) without time zone not null default CURRENT_TIMESTAMP`,
+			},
+			callback: func(table *Blueprint) {
+				table.Boolean("active").Default(true)
+				table.Int("age").Default(18)
+				table.Timestamp("created_at").Default(Raw("CURRENT_TIMESTAMP"))
+			},
+		},
+		{
+			name:  "UseCurrent_Precision",
+			table: "users",
+			sql: []string{
+				`alter table "users" add column "created_at" timestamp(6) without time zone not null default CURRENT_TIMESTAMP(6), add column "updated_at" timestamp(0) without time zone not null default CURRENT_TIMESTAMP`,
+			},
+			callback: func(table *Blueprint) {
+				table.Timestamp("created_at", 6).UseCurrent()
+				table.Timestamp("updated_at").UseCurrent().UseCurrentOnUpdate()
+			},
+		},
+		{
+			name:  "Generated",
+			table: "users",
+			sql: []string{
+				`alter table "users" add column "total" decimal(10, 2) generated always as ("price" * "quantity") stored not null`,
+				`alter table "users" alter column "name" type varchar(100), alter column "name" set not null, alter column "name" set expression as (lower("nick"))`,
+			},
+			callback: func(table *Blueprint) {
+				table.Decimal("total", 10, 2).StoredAs(`"price" * "quantity"`).Default(0)
+				table.String("name", 100).StoredAs(`lower("nick")`).Change()
+			},
+		},
+		{
+			name:  "Fulltext",
+			table: "posts",
+			sql: []string{
+				`create index "posts_title_body_fulltext" on "posts" using gin ((to_tsvector('simple', "title") || to_tsvector('simple', "body")))`,
+				`drop index "posts_summary_fulltext"`,
+			},
+			callback: func(table *Blueprint) {
+				table.Fulltext([]string{"title", "body"}, WithParser("ngram"))
+				table.DropFulltext([]string{"summary"})
+			},
+		},
+		{
+			name:  "Spatial",
+			table: "stores",
+			sql: []string{
+				`create index "stores_position_spatialindex" on "stores" using gist ("position")`,
+				`alter table "stores" add column "location" geometry(point, 4326) not null, add column "area" geometry(polygon) not null, add column "shape" geometry null`,
+			},
+			callback: func(table *Blueprint) {
+				table.SpatialIndex("position")
+				table.Point("location", 4326)
+				table.Polygon("area")
+				table.Geometry("shape").Nullable()
+			},
+		},
+		{
+			name:  "Check",
+			table: "users",
+			sql: []string{
+				`alter table "users" drop constraint "users_score_check"`,
+				`alter table "users" add column "age" integer not null`,
+				`alter table "users" add constraint "users_age_check" check ("age" >= 18)`,
+			},
+			callback: func(table *Blueprint) {
+				table.DropCheck("users_score_check")
+				table.Int("age").Check(`"age" >= 18`)
+			},
+		},
+		{
+			name:  "Escape",
+			table: `user"s`,
+			sql: []string{
+				`alter table "user""s" add column "na""me" varchar(20) not null default 'it''s', add column "type" varchar(255) check ("type" in ('a''b', 'c\d')) not null`,
+				`comment on column "user""s"."na""me" is 'user''s name'`,
+			},
+			callback: func(table *Blueprint) {
+				table.String(`na"me`, 20).Default("it's").Comment("user's name")
+				table.Enum("type", []string{"a'b", `c\d`})
+			},
+		},
+	}
+
+	newSchema := NewSchema(context.Background(), &Config{Driver: DriverPostgres})
+
+	for _, item := range cases {
+		blueprint := NewBlueprint(newSchema, item.table, item.callback)
+		sql, err := blueprint.ToSql(newSchema.GetGrammar())
+		if err != nil {
+			t.Fatal("ToSql err:", item.name, err)
+		}
+		if len(sql) != len(item.sql) {
+			t.Fatal("ToSql err:", item.name, "\nsql:", item.sql, "\ngen:", sql)
+		}
+		for i, s := range item.sql {
+			if sql[i] != s {
+				t.Fatal("ToSql err:", item.name, "\nsql:", item.sql, "\ngen:", sql)
+			}
+		}
+	}
+}
+
+func TestPostgresGrammar_unsupported(t *testing.T) {
+	newSchema := NewSchema(context.Background(), &Config{Driver: DriverPostgres})
+
+	sql, err := newSchema.Pretend(func(s *Schema) error {
+		if err := s.Table("users", func(table *Blueprint) {
+			table.Int("age")
+		}); err != nil {
+			return err
+		}
+
+		return s.Table("users", func(table *Blueprint) {
+			table.ReorderColumns("id", "name")
+		})
+	})
+	if err == nil || err.Error() != "schema err: postgres does not support reorderColumns command of users" {
+		t.Fatal("unsupported err:", err)
+	}
+	if len(sql) != 1 || sql[0] != `alter table "users" add column "age" integer not null` {
+		t.Fatal("unsupported err: the statements before the error are kept", sql)
+	}
+}
@@ -0,0 +1,1024 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// sqliteDropColumnVersion the first sqlite version supports alter table drop column
+const sqliteDropColumnVersion = "3.35.0"
+
+// sqliteTable the current definition of a sqlite table
+type sqliteTable struct {
+	version     string
+	foreignKeys bool // foreign key constraints are enforced
+	columns     []*ColumnInfo
+	indexes     []*IndexInfo
+	foreigns    []*ForeignKeyInfo
+	sql         string // the create table statement of sqlite_master
+}
+
+// sqliteDefinition a column definition or a table constraint of the create table statement
+type sqliteDefinition struct {
+	name       string // the column name, or the name of a named constraint
+	definition string
+	constraint string // the type of a table constraint, empty for a column
+	columns    []string
+	typeEnd    int // the end of the column type, where the inline primary key is inserted
+	generated  bool
+}
+
+var (
+	sqliteCheck      = regexp.MustCompile(`(?i)(?:\bconstraint\s+("(?:[^"]|"")*"|` + "`[^`]*`" + `|\[[^\]]*\]|\w+)\s+)?\bcheck\s*\(`)
+	sqlitePrimaryKey = regexp.MustCompile(`(?i)\s+(constraint\s+("[^"]*"|\S+)\s+)?primary\s+key(\s+(asc|desc))?(\s+on\s+conflict\s+\w+)?(\s+autoincrement)?`)
+	sqliteReferences = regexp.MustCompile(`(?i)\s+(constraint\s+("[^"]*"|\S+)\s+)?references\s+("[^"]*"|` + "`[^`]*`" + `|\[[^\]]*\]|\w+)(\s*\([^)]*\))?` +
+		`(\s+on\s+(delete|update)\s+(set\s+null|set\s+default|cascade|restrict|no\s+action))*(\s+match\s+\w+)?(\s+(not\s+)?deferrable(\s+initially\s+(deferred|immediate))?)?`)
+	sqliteGenerated = regexp.MustCompile(`(?i)\s(generated\s+always\s+)?as\s*\(`)
+	sqliteString    = regexp.MustCompile(`'[^']*'`)
+)
+
+// SqliteGrammar sqlite grammar,
+// the table is rebuilt when sqlite can not alter it in place.
+type SqliteGrammar struct {
+	baseGrammar
+}
+
+// Compile compile command
+func (g *SqliteGrammar) Compile(blueprint *Blueprint, command *Command) ([]string, error) {
+	return compile(g, blueprint, command)
+}
+
+// GetColumns get add columns
+func (g *SqliteGrammar) GetColumns(blueprint *Blueprint) []string {
+	var columns []string
+
+	for _, column := range blueprint.getAddedColumns() {
+		columns = append(columns, g.getColumn(blueprint, column))
+	}
+
+	return columns
+}
+
+// GetChangeColumns get change columns
+func (g *SqliteGrammar) GetChangeColumns(blueprint *Blueprint) (columns []string) {
+	for _, column := range blueprint.getChangedColumns() {
+		columns = append(columns, g.getColumn(blueprint, column))
+	}
+	return
+}
+
+// getColumn get the column definition
+func (g *SqliteGrammar) getColumn(blueprint *Blueprint, column *Column) string {
+	return g.addModifiers(g.wrap(column.Name)+" "+g.GetType(column), blueprint, column)
+}
+
+// GetType get column type
+func (g *SqliteGrammar) GetType(column *Column) string {
+	switch column.Type {
+	case ColumnTypeChar, ColumnTypeVarchar, ColumnTypeSet, ColumnTypeUuid:
+		return "varchar"
+
+	case ColumnTypeTinyText, ColumnTypeText, ColumnTypeMediumText, ColumnTypeLongText, ColumnTypeJson:
+		return "text"
+
+	case ColumnTypeBigInt, ColumnTypeInt, ColumnTypeMediumInt, ColumnTypeTinyInt, ColumnTypeSmallInt, ColumnTypeYear:
+		return "integer"
+
+	case ColumnTypeBoolean:
+		return "tinyint(1)"
+
+	case ColumnTypeFloat:
+		return "float"
+
+	case ColumnTypeDouble:
+		return "double"
+
+	case ColumnTypeDecimal:
+		return "numeric"
+
+	case ColumnTypeEnum:
+		return fmt.Sprintf("varchar check (%s in (%s))",
+			g.wrap(column.Name), g.quoteString(column.Attributes[ColumnAttrAllowed].([]string)))
+
+	case ColumnTypeDate, ColumnTypeTime:
+		return column.Type
+
+	case ColumnTypeDateTime, ColumnTypeTimestamp:
+		return "datetime"
+
+	case ColumnTypeBinary, ColumnTypeVarBinary, ColumnTypeTinyBlob, ColumnTypeBlob, ColumnTypeMediumBlob, ColumnTypeLongBlob:
+		return "blob"
+
+	case ColumnTypeGeometry, ColumnTypePoint, ColumnTypeLineString, ColumnTypePolygon,
+		ColumnTypeMultiPoint, ColumnTypeMultiPolygon, ColumnTypeGeometryCollection:
+		return column.Type
+	}
+
+	return column.Type
+}
+
+// serial check the column is an auto increment integer column
+func (g *SqliteGrammar) serial(column *Column) bool {
+	serials := []string{
+		ColumnTypeBigInt, ColumnTypeInt, ColumnTypeMediumInt, ColumnTypeSmallInt, ColumnTypeTinyInt,
+	}
+	return inArray(column.Type, serials) && column.Attributes[ColumnAttrAutoIncrement] == true
+}
+
+// addModifiers Add the column modifiers to the definition.
+func (g *SqliteGrammar) addModifiers(sql string, blueprint *Blueprint, column *Column) string {
+	// Increment
+	if g.serial(column) {
+		sql += " primary key autoincrement"
+	}
+
+	// Nullable
+	if nullable, ok := column.Attributes[ColumnAttrNullable]; ok && nullable.(bool) == true {
+		sql += " null"
+	} else {
+		sql += " not null"
+	}
+
+	// Generated
+	generated := column.compileGenerated()
+	sql += generated
+
+	// Default, a generated column has no default value, sqlite CURRENT_TIMESTAMP has no fractional seconds
+	switch def, ok := column.Attributes[ColumnAttrDefault]; {
+	case generated != "":
+	case column.Attributes[ColumnAttrUseCurrent] == true:
+		sql += " default CURRENT_TIMESTAMP"
+	case ok && def != nil:
+		sql += " default " + g.compileDefault(column)
+	}
+
+	// Collate
+	if collate, ok := column.Attributes[ColumnAttrCollate]; ok {
+		sql += " collate " + g.wrap(collate.(string))
+	}
+
+	return sql
+}
+
+// Placeholder get the bind parameter placeholder
+func (g *SqliteGrammar) Placeholder(n int) string {
+	return "?"
+}
+
+// wrap Wrap the identifier in double quotes, the double quotes in the identifier are doubled.
+func (g *SqliteGrammar) wrap(value string) string {
+	return `"` + strings.Replace(value, `"`, `""`, -1) + `"`
+}
+
+// Wrap Wrap the identifier in double quotes.
+func (g *SqliteGrammar) Wrap(value string) string {
+	return g.wrap(value)
+}
+
+func (g *SqliteGrammar) wrapTable(blueprint *Blueprint) string {
+	return g.wrap(blueprint.Prefix + blueprint.GetTable())
+}
+
+// compileDefault Compile the default value of the column.
+func (g *SqliteGrammar) compileDefault(column *Column) string {
+	value, raw := column.defaultValue()
+	return ternary(raw, value, g.quote(value))
+}
+
+// columnize wrap and join the columns
+func (g *SqliteGrammar) columnize(columns []string) string {
+	return strings.Join(arrMap(columns, g.wrap), ", ")
+}
+
+// prepare inspect the current table definition when the table needs to be rebuilt,
+// or the indexes when an index is renamed
+func (g *SqliteGrammar) prepare(blueprint *Blueprint) (err error) {
+	if blueprint.creating() || blueprint.config.DB == nil {
+		return nil
+	}
+
+	renames := blueprint.hasCommand(commandRenameIndex)
+	if !renames && !blueprint.hasCommand(commandDropColumn) && !g.rebuilds(blueprint) {
+		return nil
+	}
+
+	var (
+		db      = blueprint.config.DB
+		schema  = blueprint.schema
+		table   = blueprint.GetTable()
+		current = &sqliteTable{}
+	)
+
+	if err = db.QueryRowContext(blueprint.ctx, "select sqlite_version()").Scan(&current.version); err != nil {
+		return err
+	}
+
+	blueprint.sqliteTable = current
+	if !g.rebuilds(blueprint) {
+		if renames {
+			current.indexes, err = schema.GetIndexes(table)
+			return g.checkRenameIndexes(blueprint, err)
+		}
+		return nil
+	}
+
+	if err = db.QueryRowContext(blueprint.ctx, "pragma foreign_keys").Scan(&current.foreignKeys); err != nil {
+		return err
+	}
+
+	if current.columns, err = schema.GetColumns(table); err != nil {
+		return err
+	}
+
+	if current.indexes, err = schema.GetIndexes(table); err != nil {
+		return err
+	}
+
+	if current.foreigns, err = schema.GetForeignKeys(table); err != nil {
+		return err
+	}
+
+	err = db.QueryRowContext(blueprint.ctx, "select sql from sqlite_master where type = 'table' and name = ?", blueprint.Prefix+table).Scan(&current.sql)
+
+	return g.checkRenameIndexes(blueprint, err)
+}
+
+// checkRenameIndexes check the renamed indexes exist
+func (g *SqliteGrammar) checkRenameIndexes(blueprint *Blueprint, err error) error {
+	if err != nil {
+		return err
+	}
+
+	for _, command := range blueprint.commands {
+		if from := command.Attributes[commandAttrFrom]; command.Name == commandRenameIndex && g.currentIndex(blueprint, from.(string)) == nil {
+			return fmt.Errorf("schema err: index %s of %s not found", from, blueprint.GetTable())
+		}
+	}
+
+	return nil
+}
+
+// rebuilds check the table must be rebuilt, sqlite can not change columns, primary keys,
+// foreign keys or check constraints in place, and can not drop columns before 3.35.0
+func (g *SqliteGrammar) rebuilds(blueprint *Blueprint) bool {
+	if blueprint.creating() {
+		return false
+	}
+
+	if len(blueprint.getChangedColumns()) > 0 ||
+		blueprint.hasCommand(commandPrimary) ||
+		blueprint.hasCommand(commandDropPrimary) ||
+		blueprint.hasCommand(commandForeign) ||
+		blueprint.hasCommand(commandDropForeign) ||
+		blueprint.hasCommand(commandCheck) ||
+		blueprint.hasCommand(commandDropCheck) {
+		return true
+	}
+
+	table := blueprint.sqliteTable
+	return blueprint.hasCommand(commandDropColumn) &&
+		table != nil && table.version != "" && versionCompare(table.version, sqliteDropColumnVersion) < 0
+}
+
+// rebuilding check the command is compiled by the table rebuild,
+// the rebuild is emitted once by the first of these commands
+func (g *SqliteGrammar) rebuilding(blueprint *Blueprint, command *Command) (rebuild bool, first bool) {
+	if !g.rebuilds(blueprint) {
+		return false, false
+	}
+
+	for _, c := range blueprint.commands {
+		switch c.Name {
+		case commandAdd, commandChange, commandDropColumn, commandPrimary, commandDropPrimary, commandForeign, commandDropForeign,
+			commandCheck, commandDropCheck:
+			return true, c == command
+		}
+	}
+
+	return true, false
+}
+
+// compileRebuild Compile the create, copy, drop and rename statements to rebuild the table, with the added,
+// changed and dropped columns, the primary key and the foreign keys of the blueprint.
+// The other columns and the check and unique constraints are kept as the create table statement defines them.
+func (g *SqliteGrammar) compileRebuild(blueprint *Blueprint) ([]string, error) {
+	table := blueprint.sqliteTable
+	if table == nil {
+		return nil, fmt.Errorf("schema err: sqlite rebuilds the table %s from the current definition, config.DB is required", blueprint.GetTable())
+	}
+
+	var (
+		name        = blueprint.Prefix + blueprint.GetTable()
+		temp        = g.wrap("__temp__" + name)
+		dropped     []string
+		changed     = make(map[string]*Column)
+		primary     []string
+		foreigns    = table.foreigns
+		columns     []string
+		constraints []string
+		checks      []string // the dropped check constraints
+		copies      []string
+		inline      bool
+	)
+
+	for _, index := range table.indexes {
+		if index.Primary {
+			primary = index.Columns
+		}
+	}
+
+	for _, command := range blueprint.commands {
+		switch command.Name {
+		case commandDropColumn:
+			dropped = append(dropped, command.Attributes[commandAttrColumns].([]string)...)
+		case commandPrimary:
+			primary = command.Attributes[commandAttrColumns].([]string)
+		case commandDropPrimary:
+			primary = nil
+		case commandDropForeign:
+			foreigns = filter(foreigns, func(v *ForeignKeyInfo) bool {
+				return !g.dropsForeign(blueprint, command, v)
+			})
+		case commandDropCheck:
+			checks = append(checks, command.Attributes[commandAttrIndex].(string))
+		}
+	}
+
+	for _, column := range blueprint.getChangedColumns() {
+		changed[column.Name] = column
+	}
+
+	definitions, options := g.definitions(table)
+	for _, check := range checks {
+		if !g.hasCheck(definitions, check) {
+			return nil, fmt.Errorf("schema err: check constraint %s of %s not found", check, blueprint.GetTable())
+		}
+	}
+
+	for _, definition := range definitions {
+		if definition.constraint == "check" && definition.name != "" && inArray(strings.ToLower(definition.name), arrMap(checks, strings.ToLower)) {
+			continue
+		}
+
+		if definition.constraint != "" {
+			// the primary key and the foreign keys are compiled from the current and the blueprint definition,
+			// the constraints of the dropped columns are dropped with them
+			if (definition.constraint == "check" || definition.constraint == "unique") &&
+				len(filter(dropped, definition.mentions)) == 0 {
+				constraints = append(constraints, definition.definition)
+			}
+			continue
+		}
+
+		if inArray(definition.name, dropped) {
+			continue
+		}
+
+		if c, ok := changed[definition.name]; ok {
+			columns = append(columns, g.getColumn(blueprint, c))
+			inline = inline || g.serial(c)
+			if _, storage := c.generated(); storage == "" {
+				copies = append(copies, g.wrap(definition.name))
+			}
+			continue
+		}
+
+		sql := definition.definition
+		if column := findColumn(table.columns, definition.name); column != nil && column.AutoIncrement && len(primary) == 1 && primary[0] == column.Name {
+			at := ternary(definition.typeEnd > 0, definition.typeEnd, len(sql))
+			sql = sql[:at] + " primary key autoincrement" + sql[at:]
+			inline = true
+		}
+		columns = append(columns, sql)
+
+		if !definition.generated {
+			copies = append(copies, g.wrap(definition.name))
+		}
+	}
+
+	for _, column := range blueprint.getAddedColumns() {
+		columns = append(columns, g.getColumn(blueprint, column))
+		inline = inline || g.serial(column)
+	}
+
+	if len(primary) > 0 && !inline {
+		columns = append(columns, "primary key ("+g.columnize(primary)+")")
+	}
+
+	for _, foreign := range foreigns {
+		if len(filter(foreign.Columns, func(v string) bool { return inArray(v, dropped) })) > 0 {
+			continue
+		}
+		columns = append(columns, g.foreignKey(foreign.Columns, foreign.ForeignTable, foreign.ForeignColumns, foreign.OnDelete, foreign.OnUpdate))
+	}
+
+	columns = append(columns, g.foreignKeys(blueprint)...)
+	columns = append(columns, constraints...)
+	columns = append(columns, g.checks(blueprint)...)
+
+	statements := []string{
+		fmt.Sprintf("create table %s (%s)%s", temp, strings.Join(columns, ", "), options),
+		fmt.Sprintf("insert into %s (%s) select %s from %s", temp, strings.Join(copies, ", "), strings.Join(copies, ", "), g.wrapTable(blueprint)),
+		"drop table " + g.wrapTable(blueprint),
+		fmt.Sprintf("alter table %s rename to %s", temp, g.wrapTable(blueprint)),
+	}
+
+	for _, index := range table.indexes {
+		if index.Primary || len(filter(index.Columns, func(v string) bool { return inArray(v, dropped) })) > 0 {
+			continue
+		}
+
+		// the indexes of unique constraints are named by sqlite and can not be created by name,
+		// they are created by the kept constraints of the create table statement
+		indexName := index.Name
+		if strings.HasPrefix(indexName, "sqlite_autoindex_") {
+			if table.sql != "" {
+				continue
+			}
+			indexName = blueprint.createIndexName(ternary(index.Unique, commandUnique, commandIndex), index.Columns)
+		}
+
+		statements = append(statements, fmt.Sprintf(
+			"create %sindex %s on %s (%s)",
+			ternary(index.Unique, "unique ", ""),
+			g.wrap(indexName),
+			g.wrapTable(blueprint),
+			g.columnize(index.Columns)))
+	}
+
+	if table.foreignKeys {
+		statements = append([]string{"pragma foreign_keys = off"}, statements...)
+		statements = append(statements, "pragma foreign_keys = on")
+	}
+
+	return statements, nil
+}
+
+// definitions get the column definitions and the table constraints of the current table,
+// without the primary key and the foreign keys which are compiled by the rebuild.
+// The definitions are built from the columns when the create table statement is unknown.
+func (g *SqliteGrammar) definitions(table *sqliteTable) (definitions []*sqliteDefinition, options string) {
+	if table.sql == "" {
+		for _, column := range table.columns {
+			definition := &sqliteDefinition{name: column.Name, definition: g.wrap(column.Name) + " " + column.Type}
+			definition.typeEnd = len(definition.definition)
+			if !column.Nullable {
+				definition.definition += " not null"
+			}
+			if column.Default != nil {
+				definition.definition += " default " + *column.Default
+			}
+			definitions = append(definitions, definition)
+		}
+		return definitions, ""
+	}
+
+	items, options := splitSqliteCreate(table.sql)
+	for _, item := range items {
+		if item == "" {
+			continue
+		}
+
+		definition := &sqliteDefinition{definition: item}
+		name, rest := sqliteIdentifier(item)
+
+		switch keyword := strings.ToLower(name); {
+		case item[0] != '"' && item[0] != '`' && item[0] != '[' && inArray(keyword, []string{"constraint", "primary", "unique", "check", "foreign"}):
+			if keyword == "constraint" {
+				definition.name, rest = sqliteIdentifier(rest)
+				keyword, _ = sqliteIdentifier(rest)
+				keyword = strings.ToLower(keyword)
+			}
+			definition.constraint = keyword
+			if keyword == "unique" {
+				definition.columns = sqliteColumns(rest)
+			}
+		default:
+			definition.name = name
+			definition.definition = sqliteReferences.ReplaceAllString(sqlitePrimaryKey.ReplaceAllString(item, ""), "")
+			definition.generated = sqliteGenerated.MatchString(sqliteString.ReplaceAllString(item, "''"))
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, options
+}
+
+// hasCheck check the named check constraint is a table constraint of the definitions
+func (g *SqliteGrammar) hasCheck(definitions []*sqliteDefinition, name string) bool {
+	for _, definition := range definitions {
+		if definition.constraint == "check" && strings.EqualFold(definition.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentions check the table constraint references the column
+func (d *sqliteDefinition) mentions(column string) bool {
+	if d.constraint == "unique" {
+		return inArray(column, d.columns)
+	}
+
+	pattern := regexp.MustCompile(`(?i)(^|\W)` + regexp.QuoteMeta(column) + `(\W|$)`)
+	return pattern.MatchString(sqliteString.ReplaceAllString(d.definition, "''"))
+}
+
+// splitSqliteCreate split the column definitions and the table constraints of a create table statement,
+// and get the table options after them, such as without rowid
+func splitSqliteCreate(sql string) (items []string, options string) {
+	var (
+		depth int
+		quote byte
+		begin int
+	)
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			quote = ']'
+		case c == '(':
+			if depth++; depth == 1 {
+				begin = i + 1
+			}
+		case c == ')':
+			if depth--; depth == 0 {
+				items = append(items, strings.TrimSpace(sql[begin:i]))
+				return items, strings.TrimRight(sql[i+1:], "; \t\n")
+			}
+		case c == ',' && depth == 1:
+			items = append(items, strings.TrimSpace(sql[begin:i]))
+			begin = i + 1
+		}
+	}
+
+	return items, ""
+}
+
+// sqliteIdentifier get the leading identifier or keyword and the rest of a definition
+func sqliteIdentifier(sql string) (name string, rest string) {
+	sql = strings.TrimSpace(sql)
+	if sql == "" {
+		return "", ""
+	}
+
+	if closing := map[byte]byte{'"': '"', '`': '`', '[': ']'}[sql[0]]; closing != 0 {
+		for i := 1; i < len(sql); i++ {
+			if sql[i] != closing {
+				continue
+			}
+			// the quote is escaped by doubling it
+			if i+1 < len(sql) && sql[i+1] == closing && closing != ']' {
+				i++
+				continue
+			}
+			return strings.ReplaceAll(sql[1:i], string([]byte{closing, closing}), string([]byte{closing})), sql[i+1:]
+		}
+		return sql[1:], ""
+	}
+
+	end := strings.IndexAny(sql, " \t\n\r(")
+	if end < 0 {
+		return sql, ""
+	}
+	return sql[:end], sql[end:]
+}
+
+// sqliteColumns get the column names of a parenthesized column list
+func sqliteColumns(sql string) (columns []string) {
+	start, end := strings.IndexByte(sql, '('), strings.LastIndexByte(sql, ')')
+	if start < 0 || end < start {
+		return nil
+	}
+
+	for _, column := range strings.Split(sql[start+1:end], ",") {
+		name, _ := sqliteIdentifier(column)
+		columns = append(columns, name)
+	}
+	return
+}
+
+// dropsForeign check the drop foreign command drops the foreign key,
+// sqlite foreign keys have no name and are matched by the generated name or the columns
+func (g *SqliteGrammar) dropsForeign(blueprint *Blueprint, command *Command, foreign *ForeignKeyInfo) bool {
+	if columns, ok := command.Attributes[commandAttrColumns].([]string); ok {
+		return strings.Join(columns, ",") == strings.Join(foreign.Columns, ",")
+	}
+
+	index := command.Attributes[commandAttrIndex].(string)
+	return index == foreign.Name || index == blueprint.createIndexName(commandForeign, foreign.Columns)
+}
+
+// foreignKeys Compile the foreign key constraints of the foreign commands
+func (g *SqliteGrammar) foreignKeys(blueprint *Blueprint) (constraints []string) {
+	for _, command := range blueprint.commands {
+		if command.Name != commandForeign {
+			continue
+		}
+
+		on, _ := command.Attributes[commandAttrOn].(string)
+		onDelete, _ := command.Attributes[commandAttrOnDelete].(string)
+		onUpdate, _ := command.Attributes[commandAttrOnUpdate].(string)
+
+		constraints = append(constraints, g.foreignKey(
+			command.Attributes[commandAttrColumns].([]string),
+			blueprint.Prefix+on,
+			command.Attributes[commandAttrReferences].([]string),
+			onDelete,
+			onUpdate))
+	}
+	return
+}
+
+// foreignKey Compile a foreign key constraint
+func (g *SqliteGrammar) foreignKey(columns []string, on string, references []string, onDelete, onUpdate string) string {
+	sql := "foreign key (" + g.columnize(columns) + ") references " + g.wrap(on)
+
+	// an empty referenced column references the primary key
+	if len(filter(references, func(v string) bool { return v != "" })) > 0 {
+		sql += " (" + g.columnize(references) + ")"
+	}
+
+	if onDelete != "" && onDelete != ForeignActionNoAction {
+		sql += " on delete " + onDelete
+	}
+
+	if onUpdate != "" && onUpdate != ForeignActionNoAction {
+		sql += " on update " + onUpdate
+	}
+
+	return sql
+}
+
+// CompileCreate Compile a create table command.
+func (g *SqliteGrammar) CompileCreate(blueprint *Blueprint, command *Command) string {
+	columns := g.GetColumns(blueprint)
+
+	for _, c := range blueprint.commands {
+		if c.Name == commandPrimary {
+			columns = append(columns, "primary key ("+g.columnize(c.Attributes[commandAttrColumns].([]string))+")")
+			break
+		}
+	}
+
+	columns = append(columns, g.foreignKeys(blueprint)...)
+
+	columns = append(columns, g.checks(blueprint)...)
+
+	return fmt.Sprintf("create table %s (%s)", g.wrapTable(blueprint), strings.Join(columns, ", "))
+}
+
+// checks Compile the check constraints of the check commands
+func (g *SqliteGrammar) checks(blueprint *Blueprint) (constraints []string) {
+	for _, c := range blueprint.commands {
+		if c.Name == commandCheck {
+			constraints = append(constraints, fmt.Sprintf("constraint %s check (%s)",
+				g.wrap(c.Attributes[commandAttrIndex].(string)), c.Attributes[commandAttrExpression].(string)))
+		}
+	}
+	return
+}
+
+// CompileAdd Compile an add column command, sqlite adds a column by a statement.
+func (g *SqliteGrammar) CompileAdd(blueprint *Blueprint, command *Command) ([]string, error) {
+	if rebuild, first := g.rebuilding(blueprint, command); first {
+		return g.compileRebuild(blueprint)
+	} else if rebuild {
+		return nil, nil
+	}
+
+	return g.prefixStrings("alter table "+g.wrapTable(blueprint)+" add column ", g.GetColumns(blueprint)), nil
+}
+
+// CompileChange Compile a change column command by rebuilding the table.
+func (g *SqliteGrammar) CompileChange(blueprint *Blueprint, command *Command) ([]string, error) {
+	if _, first := g.rebuilding(blueprint, command); first {
+		return g.compileRebuild(blueprint)
+	}
+
+	return nil, nil
+}
+
+// CompileRename Rename the table to given name
+func (g *SqliteGrammar) CompileRename(blueprint *Blueprint, command *Command) string {
+	to := g.wrap(blueprint.Prefix + command.Attributes[commandAttrTo].(string))
+	return fmt.Sprintf("alter table %s rename to %s", g.wrapTable(blueprint), to)
+}
+
+// CompileRenameColumn Compile a rename column command.
+func (g *SqliteGrammar) CompileRenameColumn(blueprint *Blueprint, command *Command) string {
+	return fmt.Sprintf(
+		"alter table %s rename column %s to %s",
+		g.wrapTable(blueprint),
+		g.wrap(command.Attributes[commandAttrFrom].(string)),
+		g.wrap(command.Attributes[commandAttrTo].(string)))
+}
+
+// CompilePrimary Compile a primary key command,
+// the primary key is a part of create table, otherwise the table is rebuilt.
+func (g *SqliteGrammar) CompilePrimary(blueprint *Blueprint, command *Command) ([]string, error) {
+	if _, first := g.rebuilding(blueprint, command); first {
+		return g.compileRebuild(blueprint)
+	}
+
+	return nil, nil
+}
+
+// CompileUnique Compile a unique key command.
+func (g *SqliteGrammar) CompileUnique(blueprint *Blueprint, command *Command) string {
+	return g.compileIndex(blueprint, command, "unique index")
+}
+
+// CompileIndex Compile a plain index key command
+func (g *SqliteGrammar) CompileIndex(blueprint *Blueprint, command *Command) string {
+	return g.compileIndex(blueprint, command, "index")
+}
+
+// compileIndex Compile a create index statement
+func (g *SqliteGrammar) compileIndex(blueprint *Blueprint, command *Command, types string) string {
+	return fmt.Sprintf(
+		"create %s %s on %s (%s)",
+		types,
+		g.wrap(command.Attributes[commandAttrIndex].(string)),
+		g.wrapTable(blueprint),
+		g.columnize(command.Attributes[commandAttrColumns].([]string)))
+}
+
+// CompileDrop Compile a drop table command.
+func (g *SqliteGrammar) CompileDrop(blueprint *Blueprint, command *Command) string {
+	return "drop table " + g.wrapTable(blueprint)
+}
+
+// CompileDropIfExists Compile a drop table (if exists) command.
+func (g *SqliteGrammar) CompileDropIfExists(blueprint *Blueprint, command *Command) string {
+	return "drop table if exists " + g.wrapTable(blueprint)
+}
+
+// CompileDropColumn Compile a drop column command, before sqlite 3.35.0 the table is rebuilt.
+func (g *SqliteGrammar) CompileDropColumn(blueprint *Blueprint, command *Command) ([]string, error) {
+	if rebuild, first := g.rebuilding(blueprint, command); first {
+		return g.compileRebuild(blueprint)
+	} else if rebuild {
+		return nil, nil
+	}
+
+	if cols, ok := command.Attributes[commandAttrColumns]; ok {
+		return g.prefixStrings("alter table "+g.wrapTable(blueprint)+" drop column ", arrMap(cols.([]string), g.wrap)), nil
+	}
+
+	return nil, nil
+}
+
+// CompileDropPrimary Compile a drop primary key command by rebuilding the table.
+func (g *SqliteGrammar) CompileDropPrimary(blueprint *Blueprint, command *Command) ([]string, error) {
+	if _, first := g.rebuilding(blueprint, command); first {
+		return g.compileRebuild(blueprint)
+	}
+
+	return nil, nil
+}
+
+// CompileForeign Compile a foreign key command,
+// the foreign key is a part of create table, otherwise the table is rebuilt.
+func (g *SqliteGrammar) CompileForeign(blueprint *Blueprint, command *Command) ([]string, error) {
+	if _, first := g.rebuilding(blueprint, command); first {
+		return g.compileRebuild(blueprint)
+	}
+
+	return nil, nil
+}
+
+// CompileCheck Compile a check constraint command,
+// the check constraint is a part of create table, otherwise the table is rebuilt.
+func (g *SqliteGrammar) CompileCheck(blueprint *Blueprint, command *Command) ([]string, error) {
+	if _, first := g.rebuilding(blueprint, command); first {
+		return g.compileRebuild(blueprint)
+	}
+
+	return nil, nil
+}
+
+// CompileDropCheck Compile a drop check constraint command by rebuilding the table.
+func (g *SqliteGrammar) CompileDropCheck(blueprint *Blueprint, command *Command) ([]string, error) {
+	if _, first := g.rebuilding(blueprint, command); first {
+		return g.compileRebuild(blueprint)
+	}
+
+	return nil, nil
+}
+
+// CompileDropForeign Compile a drop foreign key command by rebuilding the table.
+func (g *SqliteGrammar) CompileDropForeign(blueprint *Blueprint, command *Command) ([]string, error) {
+	if _, first := g.rebuilding(blueprint, command); first {
+		return g.compileRebuild(blueprint)
+	}
+
+	return nil, nil
+}
+
+// CompileDropUnique Compile a drop unique key command.
+func (g *SqliteGrammar) CompileDropUnique(blueprint *Blueprint, command *Command) string {
+	return "drop index " + g.wrap(command.Attributes[commandAttrIndex].(string))
+}
+
+// CompileDropIndex Compile a drop index command.
+func (g *SqliteGrammar) CompileDropIndex(blueprint *Blueprint, command *Command) string {
+	return "drop index " + g.wrap(command.Attributes[commandAttrIndex].(string))
+}
+
+// CompileSpatialIndex sqlite has no spatial index.
+func (g *SqliteGrammar) CompileSpatialIndex(blueprint *Blueprint, command *Command) (string, error) {
+	return "", fmt.Errorf("schema err: sqlite does not support the spatial index of %s", blueprint.GetTable())
+}
+
+// CompileDropSpatialIndex sqlite has no spatial index to drop.
+func (g *SqliteGrammar) CompileDropSpatialIndex(blueprint *Blueprint, command *Command) (string, error) {
+	return "", fmt.Errorf("schema err: sqlite does not support the spatial index of %s", blueprint.GetTable())
+}
+
+// CompileFullText sqlite has no fulltext index, the fts5 virtual table is the sqlite way of fulltext search.
+func (g *SqliteGrammar) CompileFullText(blueprint *Blueprint, command *Command) (string, error) {
+	return "", fmt.Errorf("schema err: sqlite does not support the fulltext index of %s, use a fts5 virtual table instead", blueprint.GetTable())
+}
+
+// CompileDropFullText sqlite has no fulltext index to drop.
+func (g *SqliteGrammar) CompileDropFullText(blueprint *Blueprint, command *Command) (string, error) {
+	return "", fmt.Errorf("schema err: sqlite does not support the fulltext index of %s", blueprint.GetTable())
+}
+
+// CompileRenameIndex Compile a rename index command, sqlite can not rename an index,
+// the index is dropped and created by the new name.
+func (g *SqliteGrammar) CompileRenameIndex(blueprint *Blueprint, command *Command) ([]string, error) {
+	var (
+		from  = command.Attributes[commandAttrFrom].(string)
+		to    = command.Attributes[commandAttrTo].(string)
+		index = g.currentIndex(blueprint, from)
+	)
+
+	if index == nil {
+		return nil, fmt.Errorf("schema err: sqlite renames the index %s of %s by the current definition, config.DB is required", from, blueprint.GetTable())
+	}
+
+	return []string{
+		"drop index " + g.wrap(from),
+		fmt.Sprintf(
+			"create %s %s on %s (%s)",
+			ternary(index.Unique, "unique index", "index"),
+			g.wrap(to),
+			g.wrapTable(blueprint),
+			g.columnize(index.Columns)),
+	}, nil
+}
+
+// currentIndex find the current index by name
+func (g *SqliteGrammar) currentIndex(blueprint *Blueprint, name string) *IndexInfo {
+	if blueprint.sqliteTable == nil {
+		return nil
+	}
+
+	for _, index := range blueprint.sqliteTable.indexes {
+		if strings.EqualFold(index.Name, name) {
+			return index
+		}
+	}
+
+	return nil
+}
+
+// CompileTableComment sqlite does not support table comments.
+func (g *SqliteGrammar) CompileTableComment(blueprint *Blueprint, command *Command) []string {
+	return nil
+}
+
+// CompileTableExists Compile the query to determine if a table exists
+func (g *SqliteGrammar) CompileTableExists(database, table string) (string, []interface{}) {
+	return "select * from sqlite_master where type = 'table' and name = ?", []interface{}{table}
+}
+
+// CompileTables Compile the query to get the tables of a database, sqlite tables have no comment.
+func (g *SqliteGrammar) CompileTables(database string) (string, []interface{}) {
+	return "select name, null as comment from sqlite_master where type = 'table' and name not like 'sqlite_%' order by name", nil
+}
+
+// CompileColumns Compile the query to get the columns of a table
+func (g *SqliteGrammar) CompileColumns(database, table string) (string, []interface{}) {
+	return "select name, lower(case when instr(type, '(') > 0 then substr(type, 1, instr(type, '(') - 1) else type end) as type_name, " +
+			"lower(type) as type, null as length, \"notnull\" = 0 as nullable, dflt_value as \"default\", " +
+			"null as charset, null as collation, null as comment, " +
+			"pk = 1 and lower(type) = 'integer' and exists (select 1 from sqlite_master where type = 'table' and name = ? and sql like '%autoincrement%') as auto_increment, " +
+			"cid + 1 as position, null as on_update, null as generated, null as expression from pragma_table_info(?) order by cid",
+		[]interface{}{table, table}
+}
+
+// CompileIndexes Compile the query to get the indexes of a table,
+// the primary key of a rowid table has no index and is read from the columns.
+func (g *SqliteGrammar) CompileIndexes(database, table string) (string, []interface{}) {
+	return "select name, \"column\", type, \"unique\", \"primary\", position from (" +
+			"select il.name as name, ii.name as \"column\", 'btree' as type, il.\"unique\" as \"unique\", " +
+			"il.origin = 'pk' as \"primary\", ii.seqno + 1 as position, il.seq as seq " +
+			"from pragma_index_list(?) il, pragma_index_info(il.name) ii " +
+			"union all select 'primary', name, 'btree', 1, 1, pk, -1 from pragma_table_info(?) " +
+			"where pk > 0 and not exists (select 1 from pragma_index_list(?) where origin = 'pk')" +
+			") order by seq, position",
+		[]interface{}{table, table, table}
+}
+
+// CompileForeignKeys Compile the query to get the foreign keys of a table, sqlite foreign keys have no name.
+func (g *SqliteGrammar) CompileForeignKeys(database, table string) (string, []interface{}) {
+	return "select '' as name, \"from\" as \"column\", \"table\" as foreign_table, coalesce(\"to\", '') as foreign_column, " +
+			"on_update, on_delete, seq + 1 as position from pragma_foreign_key_list(?) order by id, seq",
+		[]interface{}{table}
+}
+
+// CompileChecks Compile the query to get the create table statement, the check constraints are parsed from it
+func (g *SqliteGrammar) CompileChecks(database, table string) (string, []interface{}) {
+	return "select sql from sqlite_master where type = 'table' and name = ?", []interface{}{table}
+}
+
+// parseChecks parse the check constraints of the columns and the table from the create table statement,
+// the checks without a constraint name have an empty name
+func (g *SqliteGrammar) parseChecks(create string) (checks []*CheckInfo) {
+	items, _ := splitSqliteCreate(create)
+	for _, item := range items {
+		for _, check := range sqliteCheck.FindAllStringSubmatchIndex(item, -1) {
+			var (
+				start = check[1] // after the open parenthesis
+				end   = sqliteClosing(item, start)
+				name  string
+			)
+			if end < 0 || sqliteQuoted(item, check[0]) {
+				continue
+			}
+			if check[2] >= 0 {
+				name, _ = sqliteIdentifier(item[check[2]:check[3]])
+			}
+			checks = append(checks, &CheckInfo{Name: name, Expression: strings.TrimSpace(item[start:end])})
+		}
+	}
+
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checks[i].Name < checks[j].Name
+	})
+
+	return checks
+}
+
+// sqliteClosing get the index of the parenthesis closing the expression which starts at start, -1 if not closed
+func sqliteClosing(sql string, start int) int {
+	var (
+		depth int
+		quote byte
+	)
+
+	for i := start; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			quote = ']'
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+
+	return -1
+}
+
+// sqliteQuoted check the position of the definition is in a string literal or a quoted identifier
+func sqliteQuoted(sql string, position int) bool {
+	var quote byte
+	for i := 0; i < position; i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			quote = ']'
+		}
+	}
+	return quote != 0
+}
@@ -0,0 +1,442 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+)
+
+func TestSqliteGrammar_ToSql(t *testing.T) {
+	type sqlCase struct {
+		name     string
+		table    string
+		current  *sqliteTable
+		sql      []string
+		callback func(table *Blueprint)
+	}
+
+	users := &sqliteTable{
+		version: "3.31.0",
+		columns: []*ColumnInfo{
+			{Name: "id", Type: "integer", AutoIncrement: true},
+			{Name: "name", Type: "varchar"},
+			{Name: "age", Type: "integer"},
+		},
+		indexes: []*IndexInfo{
+			{Name: "primary", Columns: []string{"id"}, Unique: true, Primary: true},
+			{Name: "users_name_index", Columns: []string{"name"}},
+			{Name: "users_age_index", Columns: []string{"age"}},
+		},
+	}
+
+	cases := []sqlCase{
+		{
+			name:  "Create",
+			table: "users",
+			sql: []string{
+				`create table "users" ("id" integer primary key autoincrement not null, "name" varchar not null default '', "type" varchar check ("type" in ('one', 'two')) not null, "price" numeric not null, "created_at" datetime null)`,
+				`create unique index "users_name_unique" on "users" ("name")`,
+			},
+			callback: func(table *Blueprint) {
+				table.create()
+				table.Id()
+				table.String("name", 20).Default("").Unique()
+				table.Enum("type", []string{"one", "two"})
+				table.Decimal("price", 10, 2)
+				table.Timestamp("created_at").Nullable()
+				table.Comment("users")
+			},
+		},
+		{
+			name:  "Create_Primary",
+			table: "users",
+			sql: []string{
+				`create table "users" ("id" integer not null, "name" varchar not null, primary key ("id", "name"))`,
+			},
+			callback: func(table *Blueprint) {
+				table.create()
+				table.Int("id")
+				table.String("name")
+				table.Primary([]string{"id", "name"})
+			},
+		},
+		{
+			name:  "Add_Drop",
+			table: "users",
+			current: &sqliteTable{
+				version: "3.40.0",
+			},
+			sql: []string{
+				`alter table "users" drop column "age"`,
+				`alter table "users" add column "email" varchar null`,
+				`alter table "users" add column "phone" varchar null`,
+				`create index "users_email_index" on "users" ("email")`,
+			},
+			callback: func(table *Blueprint) {
+				table.DropColumn("age")
+				table.String("email").Nullable().Index()
+				table.String("phone").Nullable()
+			},
+		},
+		{
+			name:    "Drop_Rename_Index",
+			table:   "users",
+			current: users,
+			sql: []string{
+				`drop index "users_age_index"`,
+				`drop index "users_name_index"`,
+				`create index "users_nickname_index" on "users" ("name")`,
+			},
+			callback: func(table *Blueprint) {
+				table.DropIndex([]string{"age"})
+				table.RenameIndex("users_name_index", "users_nickname_index")
+			},
+		},
+		{
+			name:  "RenameColumn",
+			table: "users",
+			sql: []string{
+				`alter table "users" rename column "name" to "nickname"`,
+			},
+			callback: func(table *Blueprint) {
+				table.RenameColumn("name", "nickname")
+			},
+		},
+		{
+			name:    "Rebuild_Change",
+			table:   "users",
+			current: users,
+			sql: []string{
+				`create table "__temp__users" ("id" integer primary key autoincrement not null, "name" varchar null, "age" integer not null, "email" varchar null)`,
+				`insert into "__temp__users" ("id", "name", "age") select "id", "name", "age" from "users"`,
+				`drop table "users"`,
+				`alter table "__temp__users" rename to "users"`,
+				`create index "users_name_index" on "users" ("name")`,
+				`create index "users_age_index" on "users" ("age")`,
+			},
+			callback: func(table *Blueprint) {
+				table.String("email").Nullable()
+				table.String("name", 50).Nullable().Change()
+			},
+		},
+		{
+			name:    "Rebuild_DropColumn",
+			table:   "users",
+			current: users,
+			sql: []string{
+				`create table "__temp__users" ("id" integer primary key autoincrement not null, "name" varchar not null)`,
+				`insert into "__temp__users" ("id", "name") select "id", "name" from "users"`,
+				`drop table "users"`,
+				`alter table "__temp__users" rename to "users"`,
+				`create index "users_name_index" on "users" ("name")`,
+			},
+			callback: func(table *Blueprint) {
+				table.DropColumn("age")
+			},
+		},
+		{
+			name:  "Rebuild_Primary",
+			table: "posts",
+			current: &sqliteTable{
+				version:     "3.40.0",
+				foreignKeys: true,
+				columns: []*ColumnInfo{
+					{Name: "user_id", Type: "integer"},
+					{Name: "tag", Type: "varchar"},
+				},
+			},
+			sql: []string{
+				`pragma foreign_keys = off`,
+				`create table "__temp__posts" ("user_id" integer not null, "tag" varchar not null, primary key ("user_id", "tag"))`,
+				`insert into "__temp__posts" ("user_id", "tag") select "user_id", "tag" from "posts"`,
+				`drop table "posts"`,
+				`alter table "__temp__posts" rename to "posts"`,
+				`pragma foreign_keys = on`,
+			},
+			callback: func(table *Blueprint) {
+				table.Primary([]string{"user_id", "tag"})
+			},
+		},
+		{
+			name:  "Create_Foreign",
+			table: "posts",
+			sql: []string{
+				`create table "posts" ("id" integer primary key autoincrement not null, "user_id" integer not null, foreign key ("user_id") references "users" ("id") on delete cascade)`,
+			},
+			callback: func(table *Blueprint) {
+				table.create()
+				table.Id()
+				table.UnsignedBigInt("user_id")
+				table.Foreign("user_id").References("id").On("users").CascadeOnDelete()
+			},
+		},
+		{
+			name:  "Create_Check",
+			table: "users",
+			sql: []string{
+				`create table "users" ("age" integer not null, constraint "users_age_check" check ("age" >= 18))`,
+			},
+			callback: func(table *Blueprint) {
+				table.create()
+				table.Int("age").Check(`"age" >= 18`)
+			},
+		},
+		{
+			name:  "Create_Spatial",
+			table: "stores",
+			sql: []string{
+				`create table "stores" ("location" point not null, "area" polygon null)`,
+			},
+			callback: func(table *Blueprint) {
+				table.create()
+				table.Point("location", 4326)
+				table.Polygon("area").Nullable()
+			},
+		},
+		{
+			name:  "Rebuild_Foreign",
+			table: "posts",
+			current: &sqliteTable{
+				version: "3.40.0",
+				columns: []*ColumnInfo{
+					{Name: "id", Type: "integer", AutoIncrement: true},
+					{Name: "user_id", Type: "integer"},
+					{Name: "author_id", Type: "integer", Nullable: true},
+				},
+				indexes: []*IndexInfo{
+					{Name: "primary", Columns: []string{"id"}, Unique: true, Primary: true},
+				},
+				foreigns: []*ForeignKeyInfo{
+					{Columns: []string{"user_id"}, ForeignTable: "users", ForeignColumns: []string{"id"}, OnDelete: "cascade", OnUpdate: "no action"},
+				},
+			},
+			sql: []string{
+				`create table "__temp__posts" ("id" integer primary key autoincrement not null, "user_id" integer not null, "author_id" integer, foreign key ("author_id") references "users" ("id") on delete set null)`,
+				`insert into "__temp__posts" ("id", "user_id", "author_id") select "id", "user_id", "author_id" from "posts"`,
+				`drop table "posts"`,
+				`alter table "__temp__posts" rename to "posts"`,
+			},
+			callback: func(table *Blueprint) {
+				table.DropForeign([]string{"user_id"})
+				table.Foreign("author_id").On("users").NullOnDelete()
+			},
+		},
+		{
+			name:  "Rebuild_Definition",
+			table: "users",
+			current: &sqliteTable{
+				version: "3.31.0",
+				columns: []*ColumnInfo{
+					{Name: "id", Type: "integer", AutoIncrement: true},
+					{Name: "name", Type: "varchar"},
+					{Name: "age", Type: "integer"},
+					{Name: "email", Type: "varchar", Nullable: true},
+					{Name: "team_id", Type: "integer"},
+				},
+				indexes: []*IndexInfo{
+					{Name: "primary", Columns: []string{"id"}, Unique: true, Primary: true},
+					{Name: "sqlite_autoindex_users_1", Columns: []string{"name", "age"}, Unique: true},
+					{Name: "sqlite_autoindex_users_2", Columns: []string{"email"}, Unique: true},
+				},
+				foreigns: []*ForeignKeyInfo{
+					{Columns: []string{"team_id"}, ForeignTable: "teams", ForeignColumns: []string{"id"}, OnDelete: "cascade"},
+				},
+				sql: `CREATE TABLE "users" ("id" integer primary key autoincrement not null, "name" varchar collate nocase not null, ` +
+					`"age" integer not null check ("age" > 0), "email" varchar unique, "team_id" integer not null references "teams" ("id") on delete cascade, ` +
+					`"label" varchar generated always as ("name" || ', ' || "age") virtual, ` +
+					`unique ("name", "age"), constraint "users_email_check" check ("email" like '%@%'), check ("age" < 200)) strict`,
+			},
+			sql: []string{
+				`create table "__temp__users" ("id" integer not null primary key autoincrement, "name" varchar collate nocase not null, "age" integer not null check ("age" > 0), ` +
+					`"team_id" integer not null, "label" varchar generated always as ("name" || ', ' || "age") virtual, "nickname" varchar null, ` +
+					`foreign key ("team_id") references "teams" ("id") on delete cascade, unique ("name", "age"), check ("age" < 200)) strict`,
+				`insert into "__temp__users" ("id", "name", "age", "team_id") select "id", "name", "age", "team_id" from "users"`,
+				`drop table "users"`,
+				`alter table "__temp__users" rename to "users"`,
+			},
+			callback: func(table *Blueprint) {
+				table.DropColumn("email")
+				table.String("nickname").Nullable()
+			},
+		},
+		{
+			name:  "Rebuild_Check",
+			table: "users",
+			current: &sqliteTable{
+				version: "3.40.0",
+				columns: []*ColumnInfo{
+					{Name: "id", Type: "integer", AutoIncrement: true},
+					{Name: "age", Type: "integer"},
+				},
+				indexes: []*IndexInfo{
+					{Name: "primary", Columns: []string{"id"}, Unique: true, Primary: true},
+				},
+				sql: `CREATE TABLE "users" ("id" integer primary key autoincrement not null, "age" integer not null, ` +
+					`constraint "users_age_check" check ("age" > 0), CONSTRAINT users_age_max CHECK ("age" < 200))`,
+			},
+			sql: []string{
+				`create table "__temp__users" ("id" integer not null primary key autoincrement, "age" integer not null, ` +
+					`CONSTRAINT users_age_max CHECK ("age" < 200), constraint "users_age_even" check ("age" % 2 = 0))`,
+				`insert into "__temp__users" ("id", "age") select "id", "age" from "users"`,
+				`drop table "users"`,
+				`alter table "__temp__users" rename to "users"`,
+			},
+			callback: func(table *Blueprint) {
+				table.DropCheck("Users_Age_Check")
+				table.Check("users_age_even", `"age" % 2 = 0`)
+			},
+		},
+	}
+
+	newSchema := NewSchema(context.Background(), &Config{Driver: DriverSqlite})
+
+	for _, item := range cases {
+		blueprint := NewBlueprint(newSchema, item.table, item.callback)
+		blueprint.sqliteTable = item.current
+		sql, err := blueprint.ToSql(newSchema.GetGrammar())
+		if err != nil {
+			t.Fatal("ToSql err:", item.name, err)
+		}
+		if len(sql) != len(item.sql) {
+			t.Fatal("ToSql err:", item.name, "\nsql:", item.sql, "\ngen:", sql)
+		}
+		for i, s := range item.sql {
+			if sql[i] != s {
+				t.Fatal("ToSql err:", item.name, "\nsql:", item.sql, "\ngen:", sql)
+			}
+		}
+	}
+}
+
+func TestSqliteGrammar_rebuildWithoutDB(t *testing.T) {
+	newSchema := NewSchema(context.Background(), &Config{Driver: DriverSqlite})
+	blueprint := NewBlueprint(newSchema, "users", func(table *Blueprint) {
+		table.String("name", 50).Nullable().Change()
+	})
+
+	_, err := blueprint.ToSql(newSchema.GetGrammar())
+	if err == nil || err.Error() != "schema err: sqlite rebuilds the table users from the current definition, config.DB is required" {
+		t.Fatal("rebuild without DB err:", err)
+	}
+}
+
+func TestSqliteGrammar_renameIndexWithoutDB(t *testing.T) {
+	newSchema := NewSchema(context.Background(), &Config{Driver: DriverSqlite})
+	blueprint := NewBlueprint(newSchema, "users", func(table *Blueprint) {
+		table.RenameIndex("users_name_index", "users_nickname_index")
+	})
+
+	_, err := blueprint.ToSql(newSchema.GetGrammar())
+	if err == nil || err.Error() != "schema err: sqlite renames the index users_name_index of users by the current definition, config.DB is required" {
+		t.Fatal("rename index without DB err:", err)
+	}
+}
+
+func TestSqliteGrammar_rebuildDestructive(t *testing.T) {
+	newSchema := NewSchema(context.Background(), &Config{Driver: DriverSqlite})
+	blueprint := NewBlueprint(newSchema, "users", func(table *Blueprint) {
+		table.String("email").Nullable()
+		table.String("name", 50).Nullable().Change()
+		table.Index("age")
+	})
+	blueprint.sqliteTable = &sqliteTable{
+		version: "3.40.0",
+		columns: []*ColumnInfo{{Name: "name", Type: "varchar"}, {Name: "age", Type: "integer"}},
+	}
+
+	statements, err := blueprint.toStatements(newSchema.GetGrammar())
+	if err != nil || len(statements) != 5 {
+		t.Fatal("rebuild destructive err:", err, statements)
+	}
+	for _, statement := range statements {
+		index := statement.SQL == `create index "users_age_index" on "users" ("age")`
+		if statement.Destructive == index {
+			t.Fatal("rebuild destructive err: the rebuild is destructive, the index is not", statement.SQL)
+		}
+	}
+}
+
+func TestSqliteGrammar_dropMissingCheck(t *testing.T) {
+	newSchema := NewSchema(context.Background(), &Config{Driver: DriverSqlite})
+	blueprint := NewBlueprint(newSchema, "users", func(table *Blueprint) {
+		table.DropCheck("users_age_check")
+	})
+	blueprint.sqliteTable = &sqliteTable{
+		version: "3.40.0",
+		columns: []*ColumnInfo{{Name: "age", Type: "integer"}},
+		sql:     `CREATE TABLE "users" ("age" integer not null check ("age" > 0))`,
+	}
+
+	_, err := blueprint.ToSql(newSchema.GetGrammar())
+	if err == nil || err.Error() != "schema err: check constraint users_age_check of users not found" {
+		t.Fatal("drop missing check err:", err)
+	}
+}
+
+func TestSqliteGrammar_fullText(t *testing.T) {
+	newSchema := NewSchema(context.Background(), &Config{Driver: DriverSqlite})
+	cases := []struct {
+		name     string
+		callback func(table *Blueprint)
+		err      string
+	}{
+		{"FullText", func(table *Blueprint) { table.Fulltext("body") }, "schema err: sqlite does not support the fulltext index of posts, use a fts5 virtual table instead"},
+		{"DropFullText", func(table *Blueprint) { table.DropFulltext("posts_body_fulltext") }, "schema err: sqlite does not support the fulltext index of posts"},
+	}
+
+	for _, item := range cases {
+		_, err := NewBlueprint(newSchema, "posts", item.callback).ToSql(newSchema.GetGrammar())
+		if err == nil || err.Error() != item.err {
+			t.Fatal("fulltext err:", item.name, err)
+		}
+	}
+}
+
+func TestSqliteGrammar_spatialIndex(t *testing.T) {
+	newSchema := NewSchema(context.Background(), &Config{Driver: DriverSqlite})
+	for name, callback := range map[string]func(table *Blueprint){
+		"SpatialIndex":     func(table *Blueprint) { table.SpatialIndex("location") },
+		"DropSpatialIndex": func(table *Blueprint) { table.DropSpatialIndex("places_location_spatialindex") },
+	} {
+		_, err := NewBlueprint(newSchema, "places", callback).ToSql(newSchema.GetGrammar())
+		if err == nil || err.Error() != "schema err: sqlite does not support the spatial index of places" {
+			t.Fatal("spatial index err:", name, err)
+		}
+	}
+}
+
+func TestSqliteGrammar_GetChecks(t *testing.T) {
+	db := &testDriver{onQuery: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+		if query != "select sql from sqlite_master where type = 'table' and name = ?" || len(args) != 1 || args[0].Value != "app_users" {
+			return nil, fmt.Errorf("unexpected query %s %v", query, args)
+		}
+		return &testRows{columns: []string{"sql"}, values: [][]driver.Value{{
+			`CREATE TABLE "app_users" ("id" integer primary key autoincrement not null, ` +
+				`"age" integer not null check ("age" > 0), "name" varchar default 'check (x)' constraint "users_name_check" CHECK (length("name") < 50), ` +
+				`constraint "users_period_check" check ("end_at" > ("start_at" + 1)), CONSTRAINT [users_age_max] check("age" < 200))`,
+		}}}, nil
+	}}
+
+	newSchema := NewSchema(context.Background(), &Config{DB: sql.OpenDB(db), Driver: DriverSqlite, Prefix: "app_"})
+	checks, err := newSchema.GetChecks("users")
+	if err != nil {
+		t.Fatal("GetChecks err:", err)
+	}
+
+	expected := []CheckInfo{
+		{Name: "", Expression: `"age" > 0`},
+		{Name: "users_age_max", Expression: `"age" < 200`},
+		{Name: "users_name_check", Expression: `length("name") < 50`},
+		{Name: "users_period_check", Expression: `"end_at" > ("start_at" + 1)`},
+	}
+	if len(checks) != len(expected) {
+		t.Fatal("GetChecks err:", checks)
+	}
+	for i, check := range checks {
+		if *check != expected[i] {
+			t.Fatal("GetChecks err:", "\nexpected:", expected[i], "\ngot:", *check)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package schema
+
+// IndexDefinition fluent index definition
+type IndexDefinition struct {
+	command *Command
+}
+
+// Name set the index name, default {prefix}{table}_{columns}_{type}
+func (i *IndexDefinition) Name(name string) *IndexDefinition {
+	if i.command != nil {
+		i.command.Attributes[commandAttrIndex] = name
+	}
+	return i
+}
+
+// GetCommand get the index command, nil if no columns are given
+func (i *IndexDefinition) GetCommand() *Command {
+	return i.command
+}
@@ -0,0 +1,310 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Migration a named migration with up and down callbacks
+type Migration struct {
+	Name string
+	Up   func(s *Schema) error
+	Down func(s *Schema) error
+}
+
+// MigrationStatus the state of a registered migration
+type MigrationStatus struct {
+	Name      string
+	Ran       bool
+	Batch     int
+	AppliedAt time.Time
+}
+
+// migrationRecord a row of the migrations table
+type migrationRecord struct {
+	name      string
+	batch     int
+	appliedAt time.Time
+}
+
+type Migrator struct {
+	Table string // migrations history table, default migrations
+
+	schema     *Schema
+	migrations []*Migration
+}
+
+// NewMigrator new migrator
+func NewMigrator(schema *Schema, migrations ...*Migration) *Migrator {
+	return &Migrator{
+		Table:      DefaultMigrationTable,
+		schema:     schema,
+		migrations: migrations,
+	}
+}
+
+// Register add a named migration, migrations run in the order they are registered
+func (m *Migrator) Register(name string, up func(s *Schema) error, down func(s *Schema) error) *Migrator {
+	m.migrations = append(m.migrations, &Migration{
+		Name: name,
+		Up:   up,
+		Down: down,
+	})
+	return m
+}
+
+// Migrate run all pending migrations in a new batch, return the names of the migrations that ran
+func (m *Migrator) Migrate() ([]string, error) {
+	if err := m.prepare(); err != nil {
+		return nil, err
+	}
+
+	records, err := m.records()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ran   = make(map[string]bool, len(records))
+		batch = 1
+		names []string
+	)
+
+	for _, record := range records {
+		ran[record.name] = true
+		if record.batch >= batch {
+			batch = record.batch + 1
+		}
+	}
+
+	for _, migration := range m.migrations {
+		if ran[migration.Name] {
+			continue
+		}
+
+		if migration.Up != nil {
+			if err = migration.Up(m.schema); err != nil {
+				return names, fmt.Errorf("schema err: migrate %s: %w", migration.Name, err)
+			}
+		}
+
+		if err = m.log(migration.Name, batch); err != nil {
+			return names, err
+		}
+
+		names = append(names, migration.Name)
+	}
+
+	return names, nil
+}
+
+// Rollback roll back the last batch of migrations,
+// or the last given number of migrations when steps is greater than 0
+func (m *Migrator) Rollback(steps ...int) ([]string, error) {
+	if err := m.prepare(); err != nil {
+		return nil, err
+	}
+
+	records, err := m.records()
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+
+	// newest first
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+
+	if step := varDef(steps, 0); step > 0 {
+		if step < len(records) {
+			records = records[:step]
+		}
+	} else {
+		last := records[0].batch
+		records = filter(records, func(v *migrationRecord) bool {
+			return v.batch == last
+		})
+	}
+
+	return m.rollback(records)
+}
+
+// Reset roll back all migrations
+func (m *Migrator) Reset() ([]string, error) {
+	if err := m.prepare(); err != nil {
+		return nil, err
+	}
+
+	records, err := m.records()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+
+	return m.rollback(records)
+}
+
+// Refresh roll back all migrations and run them all again
+func (m *Migrator) Refresh() ([]string, error) {
+	if _, err := m.Reset(); err != nil {
+		return nil, err
+	}
+
+	return m.Migrate()
+}
+
+// Status get the state of all registered migrations
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	if err := m.prepare(); err != nil {
+		return nil, err
+	}
+
+	records, err := m.records()
+	if err != nil {
+		return nil, err
+	}
+
+	ran := make(map[string]*migrationRecord, len(records))
+	for _, record := range records {
+		ran[record.name] = record
+	}
+
+	var status []*MigrationStatus
+
+	for _, migration := range m.migrations {
+		item := &MigrationStatus{Name: migration.Name}
+		if record, ok := ran[migration.Name]; ok {
+			item.Ran = true
+			item.Batch = record.batch
+			item.AppliedAt = record.appliedAt
+		}
+		status = append(status, item)
+	}
+
+	return status, nil
+}
+
+// rollback run the down callbacks of the given records in order
+func (m *Migrator) rollback(records []*migrationRecord) ([]string, error) {
+	var names []string
+
+	for _, record := range records {
+		migration := m.find(record.name)
+		if migration == nil {
+			return names, fmt.Errorf("schema err: migration %s not found", record.name)
+		}
+
+		if migration.Down != nil {
+			if err := migration.Down(m.schema); err != nil {
+				return names, fmt.Errorf("schema err: rollback %s: %w", migration.Name, err)
+			}
+		}
+
+		if err := m.delete(migration.Name); err != nil {
+			return names, err
+		}
+
+		names = append(names, migration.Name)
+	}
+
+	return names, nil
+}
+
+// find get registered migration by name
+func (m *Migrator) find(name string) *Migration {
+	for _, migration := range m.migrations {
+		if migration.Name == name {
+			return migration
+		}
+	}
+	return nil
+}
+
+// prepare create the migrations table if it does not exist
+func (m *Migrator) prepare() error {
+	if m.schema.config.DB == nil {
+		return errors.New("DB is nil")
+	}
+
+	exists, err := m.schema.HasTable(m.Table)
+	if err != nil || exists {
+		return err
+	}
+
+	return m.schema.Create(m.Table, func(table *Blueprint) {
+		table.Increments("id")
+		table.String("name").Unique()
+		table.Int("batch")
+		table.Timestamp("applied_at").Nullable()
+	})
+}
+
+// records get the ran migrations, oldest first
+func (m *Migrator) records() ([]*migrationRecord, error) {
+	rows, err := m.schema.config.DB.QueryContext(m.schema.ctx,
+		"select name, batch, applied_at from "+m.table()+" order by batch, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*migrationRecord
+
+	for rows.Next() {
+		var (
+			record    = &migrationRecord{}
+			appliedAt []byte
+		)
+		if err = rows.Scan(&record.name, &record.batch, &appliedAt); err != nil {
+			return nil, err
+		}
+		record.appliedAt = parseTime(string(appliedAt))
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// log insert ran migration
+func (m *Migrator) log(name string, batch int) error {
+	grammar := m.schema.grammar
+	query := fmt.Sprintf("insert into %s (name, batch, applied_at) values (%s, %s, %s)",
+		m.table(), grammar.Placeholder(1), grammar.Placeholder(2), grammar.Placeholder(3))
+
+	_, err := m.schema.config.DB.ExecContext(m.schema.ctx, query, name, batch, time.Now())
+	return err
+}
+
+// delete remove migration record
+func (m *Migrator) delete(name string) error {
+	query := "delete from " + m.table() + " where name = " + m.schema.grammar.Placeholder(1)
+
+	_, err := m.schema.config.DB.ExecContext(m.schema.ctx, query, name)
+	return err
+}
+
+// table get the wrapped migrations table name with the prefix
+func (m *Migrator) table() string {
+	return m.schema.grammar.Wrap(m.schema.config.Prefix + m.Table)
+}
+
+// parseTime parse a datetime value scanned from the database,
+// sqlite and postgres may report the timezone offset after the time
+func parseTime(value string) time.Time {
+	layouts := []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05.999999999-07",
+		"2006-01-02 15:04:05.999999999"}
+
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
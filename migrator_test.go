@@ -0,0 +1,148 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMigrator(t *testing.T) {
+	var (
+		exists  bool
+		records [][]driver.Value // name, batch, applied_at
+		db      = &testDriver{}
+	)
+
+	db.onExec = func(query string, args []driver.NamedValue) {
+		switch {
+		case strings.HasPrefix(query, "create table `app_migrations`"):
+			exists = true
+		case strings.HasPrefix(query, "insert into `app_migrations`"):
+			records = append(records, []driver.Value{args[0].Value, args[1].Value, args[2].Value})
+		case strings.HasPrefix(query, "delete from `app_migrations`"):
+			records = filter(records, func(v []driver.Value) bool { return v[0] != args[0].Value })
+		}
+	}
+	db.onQuery = func(query string, args []driver.NamedValue) (driver.Rows, error) {
+		switch {
+		case strings.HasPrefix(query, "select * from information_schema.tables"):
+			rows := &testRows{columns: []string{"table_name"}}
+			if exists {
+				rows.values = [][]driver.Value{{"app_migrations"}}
+			}
+			return rows, nil
+		case query == "select name, batch, applied_at from `app_migrations` order by batch, id":
+			return &testRows{columns: []string{"name", "batch", "applied_at"}, values: append([][]driver.Value(nil), records...)}, nil
+		}
+		return nil, errors.New("unexpected query " + query)
+	}
+
+	var (
+		newSchema = NewSchema(context.Background(), &Config{DB: sql.OpenDB(db), Database: "app", Prefix: "app_"})
+		migrator  = NewMigrator(newSchema)
+		create    = func(table string) {
+			migrator.Register("create_"+table, func(s *Schema) error {
+				return s.Create(table, func(table *Blueprint) { table.Id() })
+			}, func(s *Schema) error {
+				return s.Drop(table)
+			})
+		}
+	)
+
+	create("users")
+	create("posts")
+
+	check := func(name string, names []string, err error, expected ...string) {
+		if err != nil || fmt.Sprint(names) != fmt.Sprint(expected) {
+			t.Fatal("Migrator err:", name, err, "\nexpected:", expected, "\nran:", names)
+		}
+	}
+
+	names, err := migrator.Migrate()
+	check("Migrate", names, err, "create_users", "create_posts")
+
+	names, err = migrator.Migrate()
+	check("Migrate_Nothing", names, err)
+
+	create("tags")
+	names, err = migrator.Migrate()
+	check("Migrate_Batch", names, err, "create_tags")
+
+	status, err := migrator.Status()
+	if err != nil || len(status) != 3 {
+		t.Fatal("Status err:", err, status)
+	}
+	for i, batch := range []int{1, 1, 2} {
+		if !status[i].Ran || status[i].Batch != batch || status[i].AppliedAt.IsZero() {
+			t.Fatal("Status err:", status[i])
+		}
+	}
+
+	names, err = migrator.Rollback()
+	check("Rollback", names, err, "create_tags")
+
+	names, err = migrator.Migrate()
+	check("Migrate_Again", names, err, "create_tags")
+
+	names, err = migrator.Rollback(2)
+	check("Rollback_Steps", names, err, "create_tags", "create_posts")
+
+	status, err = migrator.Status()
+	if err != nil || !status[0].Ran || status[1].Ran || status[1].Batch != 0 || status[2].Ran {
+		t.Fatal("Status err:", err, status)
+	}
+
+	names, err = migrator.Reset()
+	check("Reset", names, err, "create_users")
+
+	failed := errors.New("failed")
+	migrator.Register("broken", func(s *Schema) error { return failed }, nil)
+	names, err = migrator.Migrate()
+	if !errors.Is(err, failed) || fmt.Sprint(names) != "[create_users create_posts create_tags]" {
+		t.Fatal("Migrate err: broken", err, names)
+	}
+	if len(records) != 3 {
+		t.Fatal("Migrate err: the broken migration is logged", records)
+	}
+
+	expected := []string{
+		"create table `app_migrations` (`id` int unsigned not null auto_increment primary key, `name` varchar(255) not null, `batch` int not null, `applied_at` timestamp null) default character set utf8mb4 collate 'utf8mb4_unicode_ci' engine = InnoDB",
+		"alter table `app_migrations` add unique `app_migrations_name_unique`(`name`)",
+		"create table `app_users` (`id` bigint unsigned not null auto_increment primary key) default character set utf8mb4 collate 'utf8mb4_unicode_ci' engine = InnoDB",
+	}
+	for i, s := range expected {
+		if db.executed[i] != s {
+			t.Fatal("Migrator err:", "\nexpected:", s, "\nexecuted:", db.executed[i])
+		}
+	}
+	if !inArray("drop table `app_tags`", db.executed) || !inArray("drop table `app_posts`", db.executed) {
+		t.Fatal("Migrator err: rollback", db.executed)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	utc := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	cases := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"2024-05-06T07:08:09Z", utc},
+		{"2024-05-06 15:08:09+08:00", utc},
+		{"2024-05-06 02:08:09.5-05:00", utc.Add(500 * time.Millisecond)},
+		{"2024-05-06 09:08:09+02", utc},
+		{"2024-05-06 07:08:09", time.Date(2024, 5, 6, 7, 8, 9, 0, time.Local)},
+		{"2024-05-06 07:08:09.123", time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.Local)},
+		{"invalid", time.Time{}},
+	}
+
+	for _, item := range cases {
+		if parsed := parseTime(item.value); !parsed.Equal(item.expected) {
+			t.Fatal("parseTime err:", item.value, parsed)
+		}
+	}
+}
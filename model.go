@@ -0,0 +1,465 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// modelTag the column definition of a struct field tag
+type modelTag struct {
+	skip          bool
+	column        string
+	types         string
+	size          int
+	precision     int
+	scale         int
+	nullable      *bool
+	def           *string
+	comment       string
+	primary       bool
+	autoIncrement *bool
+	unsigned      bool
+	indexes       []modelIndex
+	embedded      bool
+	prefix        string
+	serializer    bool
+}
+
+// modelIndex an index of a field, fields of the same index name are a composite index
+type modelIndex struct {
+	name   string
+	unique bool
+}
+
+// AutoMigrate Create the tables of models, or add the missing columns and indexes to the existing tables.
+// The table name is the TableName method of the model or the plural snake case of the struct name,
+// the columns are read from the schema tags, or the gorm tags, or the goframe orm tags of the fields.
+func (s *Schema) AutoMigrate(models ...interface{}) error {
+	for _, model := range models {
+		if err := s.autoMigrate(model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// autoMigrate migrate a model
+func (s *Schema) autoMigrate(model interface{}) error {
+	var (
+		table     = modelTable(model)
+		blueprint = NewBlueprint(s, table)
+	)
+
+	if err := blueprint.model(model); err != nil {
+		return err
+	}
+
+	exists, err := s.HasTable(table)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		blueprint.commands = append([]*Command{blueprint.createCommand(commandCreate)}, blueprint.commands...)
+		return s.build(blueprint)
+	}
+
+	columns, err := s.GetColumns(table)
+	if err != nil {
+		return err
+	}
+
+	indexes, err := s.GetIndexes(table)
+	if err != nil {
+		return err
+	}
+
+	var commands []*Command
+
+	blueprint.columns = filter(blueprint.columns, func(v *Column) bool {
+		return findColumn(columns, v.Name) == nil
+	})
+	if len(blueprint.columns) > 0 {
+		commands = append(commands, blueprint.createCommand(commandAdd))
+	}
+
+	for _, command := range blueprint.commands {
+		var (
+			cols    = command.Attributes[commandAttrColumns].([]string)
+			primary = command.Name == commandPrimary
+		)
+		if findIndex(indexes, command.Attributes[commandAttrIndex].(string), cols, primary, command.Name != commandIndex) == nil {
+			commands = append(commands, command)
+		}
+	}
+
+	if blueprint.commands = commands; len(commands) == 0 {
+		return nil
+	}
+
+	return s.build(blueprint)
+}
+
+// model Add the columns and indexes of the struct fields of model
+func (b *Blueprint) model(model interface{}) error {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("schema err: model %T is not a struct", model)
+	}
+
+	var (
+		primaries []string
+		indexes   []string
+		columns   = map[string][]string{}
+		unique    = map[string]bool{}
+		named     = map[string]bool{}
+		manual    = map[string]bool{} // the columns of autoIncrement:false
+	)
+
+	err := b.modelFields(t, "", func(column *Column, tag *modelTag) {
+		if tag.primary {
+			primaries = append(primaries, column.Name)
+		}
+		if tag.autoIncrement != nil && !*tag.autoIncrement {
+			manual[column.Name] = true
+		}
+
+		for _, index := range tag.indexes {
+			name := ternary(index.name == "", column.Name, index.name)
+			if _, ok := columns[name]; !ok {
+				indexes = append(indexes, name)
+			}
+			columns[name] = append(columns[name], column.Name)
+			unique[name] = index.unique
+			named[name] = index.name != ""
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	// the id column is the primary key by default
+	if len(primaries) == 0 && b.findColumn("id") != nil {
+		primaries = []string{"id"}
+	}
+
+	// a single integer primary key is auto increment unless the tag is autoIncrement:false,
+	// and the primary key is a modifier of the column
+	if len(primaries) == 1 {
+		if column := b.findColumn(primaries[0]); isInteger(column) && !manual[column.Name] {
+			column.Attributes[ColumnAttrAutoIncrement] = true
+			primaries = nil
+		}
+	}
+
+	if len(primaries) > 0 {
+		b.Primary(primaries)
+	}
+
+	// the named indexes of the tags keep the name
+	for _, name := range indexes {
+		index := ternary(unique[name], b.Unique, b.Index)(columns[name])
+		if named[name] {
+			index.Name(name)
+		}
+	}
+
+	return nil
+}
+
+// modelFields Add the columns of the fields of struct type t, embedded structs are flattened
+func (b *Blueprint) modelFields(t reflect.Type, prefix string, callback func(column *Column, tag *modelTag)) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		// unexported embedded structs are flattened
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		tag := parseModelTag(field)
+		if tag.skip {
+			continue
+		}
+
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if (field.Anonymous || tag.embedded) && ft.Kind() == reflect.Struct && tag.types == "" && !isValueStruct(ft) {
+			if err := b.modelFields(ft, prefix+tag.prefix, callback); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		// the relations of gorm, a struct or a slice of structs, are not columns
+		if tag.types == "" && !tag.serializer && isRelation(ft) {
+			continue
+		}
+
+		column, err := b.modelColumn(prefix+ternary(tag.column == "", snake(field.Name), tag.column), field.Type, tag)
+		if err != nil {
+			return fmt.Errorf("schema err: field %s.%s: %w", t.Name(), field.Name, err)
+		}
+
+		callback(column, tag)
+	}
+
+	return nil
+}
+
+// modelColumn Add the column of a field, the type is the tag type or derived from the go type
+func (b *Blueprint) modelColumn(name string, t reflect.Type, tag *modelTag) (column *Column, err error) {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+
+	if tag.types != "" {
+		// the attributes parsed from the type, such as the length, precision and enum values,
+		// the nullable and comment are set by the tag
+		parsed := (&ColumnInfo{Name: name, Type: tag.types}).toColumn()
+		delete(parsed.Attributes, ColumnAttrNullable)
+		delete(parsed.Attributes, ColumnAttrComment)
+
+		column = b.AddColumn(parsed.Type, name, parsed.Attributes)
+	} else if column, nullable, err = b.modelTypeColumn(name, t, tag, nullable); err != nil {
+		return nil, err
+	}
+
+	if tag.unsigned {
+		column.Attributes[ColumnAttrUnsigned] = true
+	}
+	if tag.autoIncrement != nil {
+		column.Attributes[ColumnAttrAutoIncrement] = *tag.autoIncrement
+	}
+	if tag.nullable != nil {
+		nullable = *tag.nullable
+	}
+	if nullable {
+		column.Nullable()
+	}
+	if tag.def != nil {
+		column.Default(modelDefault(*tag.def))
+	}
+	if tag.comment != "" {
+		column.Comment(tag.comment)
+	}
+
+	return column, nil
+}
+
+// modelTypeColumn Add the column of a go type
+func (b *Blueprint) modelTypeColumn(name string, t reflect.Type, tag *modelTag, nullable bool) (*Column, bool, error) {
+	var (
+		size      = ternary(tag.size > 0, tag.size, DefaultStringLength)
+		precision = ternary(tag.precision > 0, tag.precision, 8)
+		scale     = ternary(tag.scale > 0, tag.scale, 2)
+	)
+
+	// the nullable types of database/sql and gorm.DeletedAt
+	switch t.PkgPath() + "." + t.Name() {
+	case "database/sql.NullString":
+		return b.String(name, size), true, nil
+	case "database/sql.NullBool":
+		return b.Boolean(name), true, nil
+	case "database/sql.NullByte":
+		return b.UnsignedTinyInt(name), true, nil
+	case "database/sql.NullInt16":
+		return b.SmallInt(name), true, nil
+	case "database/sql.NullInt32":
+		return b.Int(name), true, nil
+	case "database/sql.NullInt64":
+		return b.BigInt(name), true, nil
+	case "database/sql.NullFloat64":
+		return b.Double(name, precision, scale), true, nil
+	case "database/sql.NullTime", "gorm.io/gorm.DeletedAt":
+		return b.DateTime(name), true, nil
+	case "time.Time":
+		return b.DateTime(name), nullable, nil
+	case "encoding/json.RawMessage":
+		return b.Json(name), nullable, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return b.String(name, size), nullable, nil
+	case reflect.Bool:
+		return b.Boolean(name), nullable, nil
+	case reflect.Int8:
+		return b.TinyInt(name), nullable, nil
+	case reflect.Int16:
+		return b.SmallInt(name), nullable, nil
+	case reflect.Int32:
+		return b.Int(name), nullable, nil
+	case reflect.Int, reflect.Int64:
+		return b.BigInt(name), nullable, nil
+	case reflect.Uint8:
+		return b.UnsignedTinyInt(name), nullable, nil
+	case reflect.Uint16:
+		return b.UnsignedSmallInt(name), nullable, nil
+	case reflect.Uint32:
+		return b.UnsignedInt(name), nullable, nil
+	case reflect.Uint, reflect.Uint64:
+		return b.UnsignedBigInt(name), nullable, nil
+	case reflect.Float32:
+		return b.Float(name, precision, scale), nullable, nil
+	case reflect.Float64:
+		return b.Double(name, precision, scale), nullable, nil
+	case reflect.Slice:
+		// the bytes are varbinary of the size, or longblob
+		if t.Elem().Kind() == reflect.Uint8 {
+			if tag.size > 0 {
+				return b.VarBinary(name, tag.size), nullable, nil
+			}
+			return b.LongBlob(name), nullable, nil
+		}
+		return b.Json(name), nullable, nil
+	case reflect.Array, reflect.Map, reflect.Struct:
+		return b.Json(name), nullable, nil
+	}
+
+	return nil, false, fmt.Errorf("unsupported go type %s", t)
+}
+
+// parseModelTag parse the schema tag, or the gorm tag, or the goframe orm tag of a field,
+// the schema tag has the gorm syntax, e.g. `schema:"column:name;type:varchar(50);not null;default:guest;comment:name;index"`
+func parseModelTag(field reflect.StructField) *modelTag {
+	tag := &modelTag{}
+
+	value, ok := field.Tag.Lookup("schema")
+	if !ok {
+		value, ok = field.Tag.Lookup("gorm")
+	}
+
+	if !ok {
+		// goframe: `orm:"name,primary"`
+		if value, ok = field.Tag.Lookup("orm"); ok {
+			values := strings.Split(value, ",")
+			tag.skip = values[0] == "-"
+			tag.column = strings.TrimSpace(values[0])
+			tag.primary = inArray("primary", values[1:])
+		}
+		return tag
+	}
+
+	if value == "-" || value == "-:all" || value == "-:migration" {
+		tag.skip = true
+		return tag
+	}
+
+	for _, item := range splitTag(value) {
+		var (
+			key, val, _ = strings.Cut(item, ":")
+			flag        = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(key), "_", ""))
+		)
+
+		switch flag {
+		case "column":
+			tag.column = val
+		case "type":
+			tag.types = val
+		case "size":
+			tag.size, _ = strconv.Atoi(val)
+		case "precision":
+			tag.precision, _ = strconv.Atoi(val)
+		case "scale":
+			tag.scale, _ = strconv.Atoi(val)
+		case "notnull", "not null":
+			nullable := false
+			tag.nullable = &nullable
+		case "null", "nullable":
+			nullable := true
+			tag.nullable = &nullable
+		case "default":
+			tag.def = &val
+		case "comment":
+			tag.comment = val
+		case "primarykey":
+			tag.primary = true
+		case "autoincrement":
+			// autoIncrement or autoIncrement:true, autoIncrement:false disables the auto increment of the primary key
+			autoIncrement := !strings.EqualFold(strings.TrimSpace(val), "false")
+			tag.autoIncrement = &autoIncrement
+		case "unsigned":
+			tag.unsigned = true
+		case "index":
+			tag.indexes = append(tag.indexes, modelIndex{name: indexName(val)})
+		case "unique", "uniqueindex":
+			tag.indexes = append(tag.indexes, modelIndex{name: indexName(val), unique: true})
+		case "embedded":
+			tag.embedded = true
+		case "embeddedprefix":
+			tag.prefix = val
+		case "serializer":
+			tag.serializer = true
+		}
+	}
+
+	return tag
+}
+
+// splitTag split a gorm style tag by semicolons, \; is escaped
+func splitTag(value string) (items []string) {
+	for _, item := range strings.Split(strings.ReplaceAll(value, `\;`, "\x00"), ";") {
+		if item = strings.TrimSpace(strings.ReplaceAll(item, "\x00", ";")); item != "" {
+			items = append(items, item)
+		}
+	}
+	return
+}
+
+// indexName get the index name of an index tag value, e.g. idx_name,sort:desc
+func indexName(value string) string {
+	name, _, _ := strings.Cut(value, ",")
+	return strings.TrimSpace(name)
+}
+
+// modelDefault convert the tag default to the column default, expressions such as CURRENT_TIMESTAMP are raw
+func modelDefault(value string) interface{} {
+	if value = strings.TrimSpace(value); expressionDefault.MatchString(value) {
+		return Raw(value)
+	}
+
+	return unquote(value)
+}
+
+// isValueStruct check the struct is a column value rather than embedded fields, e.g. time.Time
+func isValueStruct(t reflect.Type) bool {
+	switch t.PkgPath() + "." + t.Name() {
+	case "time.Time", "gorm.io/gorm.DeletedAt":
+		return true
+	}
+	return t.PkgPath() == "database/sql" || t.ConvertibleTo(reflect.TypeOf(time.Time{}))
+}
+
+// isRelation check the type is a struct or a slice of structs, which is a relation of models
+func isRelation(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !isValueStruct(t)
+}
+
+// isInteger check the column is an integer column
+func isInteger(column *Column) bool {
+	return column != nil && inArray(column.Type, []string{
+		ColumnTypeBigInt, ColumnTypeInt, ColumnTypeMediumInt, ColumnTypeSmallInt, ColumnTypeTinyInt,
+	})
+}
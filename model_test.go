@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+type testModel struct {
+	ID        uint
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+type testUser struct {
+	testModel
+	Name     string         `schema:"size:50;not null;default:'';comment:姓名;index:idx_name_age"`
+	Age      int32          `gorm:"index:idx_name_age"`
+	Email    sql.NullString `gorm:"size:100;uniqueIndex"`
+	Price    float64        `gorm:"type:decimal(10,2);unsigned"`
+	Active   bool           `gorm:"default:true"`
+	Type     string         `gorm:"type:enum('one','two');default:one"`
+	Nick     string         `orm:"nickname"`
+	Tags     []string
+	Profile  *testProfile
+	Password string `gorm:"-"`
+	password string
+}
+
+type testProfile struct {
+	ID uint
+}
+
+type testLog struct {
+	ID        int64   `gorm:"primaryKey;autoIncrement:false"`
+	Level     string  `gorm:"type:ENUM('Info','Error');uniqueIndex:uk_log_level_at"`
+	Payload   []byte  `gorm:"type:longblob"`
+	Hash      []byte  `gorm:"type:varbinary(32)"`
+	Score     float64 `gorm:"type:double"`
+	Data      []byte
+	Token     []byte    `gorm:"size:64"`
+	CreatedAt time.Time `gorm:"type:datetime(3);uniqueIndex:uk_log_level_at"`
+}
+
+type testTag struct {
+	PostId uint64 `gorm:"primaryKey"`
+	Tag    string `gorm:"primaryKey;size:20"`
+}
+
+func (t *testTag) TableName() string {
+	return "post_tags"
+}
+
+func TestBlueprint_model(t *testing.T) {
+	type modelCase struct {
+		name  string
+		model interface{}
+		sql   []string
+	}
+
+	cases := []modelCase{
+		{
+			name:  "User",
+			model: &testUser{},
+			sql: []string{
+				"create table `test_users` (`id` bigint unsigned not null auto_increment primary key, `created_at` datetime not null, `updated_at` datetime null, `name` varchar(50) not null default '' comment '姓名', `age` int not null, `email` varchar(100) null, `price` decimal(10, 2) unsigned not null, `active` tinyint(1) not null default 1, `type` enum('one', 'two') not null default 'one', `nickname` varchar(255) not null, `tags` json not null) default character set utf8mb4 collate 'utf8mb4_unicode_ci' engine = InnoDB",
+				"alter table `test_users` add index `idx_name_age`(`name`, `age`)",
+				"alter table `test_users` add unique `test_users_email_unique`(`email`)",
+			},
+		},
+		{
+			name:  "Tag",
+			model: &testTag{},
+			sql: []string{
+				"create table `post_tags` (`post_id` bigint unsigned not null, `tag` varchar(20) not null) default character set utf8mb4 collate 'utf8mb4_unicode_ci' engine = InnoDB",
+				"alter table `post_tags` add primary key `post_tags_post_id_tag_primary`(`post_id`, `tag`)",
+			},
+		},
+		{
+			name:  "Log",
+			model: &testLog{},
+			sql: []string{
+				"create table `test_logs` (`id` bigint not null, `level` enum('Info', 'Error') not null, `payload` longblob not null, `hash` varbinary(32) not null, `score` double not null, `data` longblob not null, `token` varbinary(64) not null, `created_at` datetime(3) not null) default character set utf8mb4 collate 'utf8mb4_unicode_ci' engine = InnoDB",
+				"alter table `test_logs` add primary key `test_logs_id_primary`(`id`)",
+				"alter table `test_logs` add unique `uk_log_level_at`(`level`, `created_at`)",
+			},
+		},
+	}
+
+	newSchema := NewSchema(context.Background(), &Config{})
+
+	for _, item := range cases {
+		blueprint := NewBlueprint(newSchema, modelTable(item.model))
+		blueprint.create()
+		if err := blueprint.model(item.model); err != nil {
+			t.Fatal("model err:", item.name, err)
+		}
+		sql, err := blueprint.ToSql(newSchema.GetGrammar())
+		if err != nil {
+			t.Fatal("ToSql err:", item.name, err)
+		}
+		if len(sql) != len(item.sql) {
+			t.Fatal("ToSql err:", item.name, "\nsql:", item.sql, "\ngen:", sql)
+		}
+		for i, s := range item.sql {
+			if sql[i] != s {
+				t.Fatal("ToSql err:", item.name, "\nsql:", item.sql, "\ngen:", sql)
+			}
+		}
+	}
+
+	if err := NewBlueprint(newSchema, "users").model(1); err == nil {
+		t.Fatal("model err: int is not a model")
+	}
+}
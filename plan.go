@@ -0,0 +1,59 @@
+package schema
+
+import (
+	"context"
+)
+
+// Statement a statement of a migration plan
+type Statement struct {
+	Table       string   // table name without prefix
+	Command     string   // the blueprint command which compiles the statement, e.g. create, add, change, dropColumn
+	Destructive bool     // the statement may lose data: drop table, drop column, change a column to a narrower type, or rebuild the table
+	Columns     []string // the affected columns
+	SQL         string
+}
+
+// Plan the statements of a migration which are reviewed before being applied
+type Plan struct {
+	Statements []*Statement
+
+	schema *Schema
+}
+
+// Plan Run the callback without executing the statements, and return the plan of the statements.
+// The database is still queried to inspect the tables when config.DB is set.
+func (s *Schema) Plan(callback func(s *Schema) error) (*Plan, error) {
+	pretend := &Schema{
+		ctx:        s.ctx,
+		config:     s.config,
+		grammar:    s.grammar,
+		pretending: true,
+	}
+
+	err := callback(pretend)
+
+	return &Plan{Statements: pretend.statements, schema: s}, err
+}
+
+// Destructive get the statements which may lose data
+func (p *Plan) Destructive() []*Statement {
+	return filter(p.Statements, func(statement *Statement) bool {
+		return statement.Destructive
+	})
+}
+
+// Apply Execute the statements of the plan in order.
+// The approve callback receives the statements and returns the statements to execute,
+// an error of the callback rejects the plan and nothing is executed.
+func (p *Plan) Apply(ctx context.Context, approve ...func(statements []*Statement) ([]*Statement, error)) error {
+	statements := p.Statements
+
+	for _, callback := range approve {
+		var err error
+		if statements, err = callback(statements); err != nil {
+			return err
+		}
+	}
+
+	return p.schema.exec(ctx, statements)
+}
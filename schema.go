@@ -4,28 +4,42 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"log/slog"
+	"time"
 )
 
 type Config struct {
 	DB           *sql.DB // database handle
+	Driver       string  // database driver: mysql, postgres, sqlite, default mysql
+	Grammar      Grammar // custom grammar, takes precedence over Driver
 	Database     string  // current database
 	Prefix       string  // database prefix
 	Engine       string  // engin, default InnoDB
 	Charset      string  // charset, default utf8mb4
 	Collation    string  // collation, default utf8mb4_unicode_ci
 	StringLength int     // string length, default 255
+
+	BeforeStatement func(ctx context.Context, statement *Statement)                                   // called before a statement is executed
+	AfterStatement  func(ctx context.Context, statement *Statement, elapsed time.Duration, err error) // called after a statement is executed
+	OnError         func(ctx context.Context, statement *Statement, err error)                        // called when a statement fails
+	Logger          *slog.Logger                                                                      // log the executed statements
 }
 
 type Schema struct {
-	ctx    context.Context
-	config *Config
+	ctx     context.Context
+	config  *Config
+	grammar Grammar
+
+	pretending bool         // collect the statements instead of executing them
+	statements []*Statement // the collected statements
 }
 
 // NewSchema new schema
 func NewSchema(ctx context.Context, config *Config) *Schema {
 	return &Schema{
-		ctx:    ctx,
-		config: config,
+		ctx:     ctx,
+		config:  config,
+		grammar: newGrammar(config),
 	}
 }
 
@@ -66,11 +80,13 @@ func (s *Schema) DropColumns(table string, columns ...string) error {
 
 // HasTable check table exists
 func (s *Schema) HasTable(table string) (bool, error) {
-	if s.config.Database == "" {
-		return false, errors.New("schema err: config.Database is empty")
+	if err := s.checkDatabase(); err != nil {
+		return false, err
 	}
 
-	rows, err := s.config.DB.Query(localGrammar.CompileTableExists(), s.config.Database, s.config.Prefix+table)
+	query, args := s.grammar.CompileTableExists(s.config.Database, s.config.Prefix+table)
+
+	rows, err := s.config.DB.Query(query, args...)
 	if err != nil {
 		return false, err
 	}
@@ -84,6 +100,18 @@ func (s *Schema) HasTable(table string) (bool, error) {
 	return i > 0, nil
 }
 
+// checkDatabase check config.DB and config.Database are set before the tables are inspected,
+// sqlite has no database name
+func (s *Schema) checkDatabase() error {
+	if s.config.DB == nil {
+		return errors.New("DB is nil")
+	}
+	if s.config.Database == "" && s.config.Driver != DriverSqlite {
+		return errors.New("schema err: config.Database is empty")
+	}
+	return nil
+}
+
 // Rename table
 func (s *Schema) Rename(from string, to string) error {
 	return s.build(tap(NewBlueprint(s, from), func(table *Blueprint) {
@@ -92,5 +120,74 @@ func (s *Schema) Rename(from string, to string) error {
 }
 
 func (s *Schema) build(blueprint *Blueprint) error {
-	return blueprint.build(localGrammar)
+	return blueprint.build(s.grammar)
+}
+
+// Pretend Run the callback without executing the statements, and return the statements that would be executed.
+// The database is still queried to inspect the tables, the inspections fail when config.DB is nil.
+func (s *Schema) Pretend(callback func(s *Schema) error) ([]string, error) {
+	plan, err := s.Plan(callback)
+
+	var sql []string
+	for _, statement := range plan.Statements {
+		sql = append(sql, statement.SQL)
+	}
+
+	return sql, err
+}
+
+// exec Execute the statements in order.
+func (s *Schema) exec(ctx context.Context, statements []*Statement) error {
+	if s.config.DB == nil {
+		return errors.New("DB is nil")
+	}
+
+	for i, statement := range statements {
+		if err := s.execStatement(ctx, statement); err != nil {
+			return newStatementError(statement, i, err)
+		}
+	}
+
+	return nil
+}
+
+// execStatement Execute a statement with the hooks and the logger of the config.
+func (s *Schema) execStatement(ctx context.Context, statement *Statement) error {
+	if s.config.BeforeStatement != nil {
+		s.config.BeforeStatement(ctx, statement)
+	}
+
+	start := time.Now()
+	_, err := s.config.DB.ExecContext(ctx, statement.SQL)
+	elapsed := time.Since(start)
+
+	if s.config.AfterStatement != nil {
+		s.config.AfterStatement(ctx, statement, elapsed, err)
+	}
+
+	if err != nil && s.config.OnError != nil {
+		s.config.OnError(ctx, statement, err)
+	}
+
+	if s.config.Logger != nil {
+		attrs := []slog.Attr{
+			slog.String("table", statement.Table),
+			slog.String("command", statement.Command),
+			slog.Duration("elapsed", elapsed),
+			slog.String("sql", statement.SQL),
+		}
+
+		if err != nil {
+			s.config.Logger.LogAttrs(ctx, slog.LevelError, "schema statement failed", append(attrs, slog.Any("error", err))...)
+		} else {
+			s.config.Logger.LogAttrs(ctx, slog.LevelInfo, "schema statement", attrs...)
+		}
+	}
+
+	return err
+}
+
+// GetGrammar get the grammar of the schema
+func (s *Schema) GetGrammar() Grammar {
+	return s.grammar
 }
@@ -0,0 +1,552 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TableInfo a table of the database
+type TableInfo struct {
+	Name    string // table name, with the prefix
+	Comment string // table comment
+}
+
+// ColumnInfo a column of an existing table
+type ColumnInfo struct {
+	Name          string
+	TypeName      string  // data type, e.g. varchar
+	Type          string  // full column type, e.g. varchar(255), int unsigned
+	Length        int64   // character maximum length, 0 if not a string column
+	Nullable      bool    // is nullable
+	Default       *string // default value as reported by the database without the postgres type cast, nil if the column has no default
+	Charset       string  // character set
+	Collation     string  // collation
+	Comment       string  // column comment
+	AutoIncrement bool    // is auto increment
+	Position      int     // ordinal position, start from 1
+	OnUpdate      string  // on update expression, e.g. CURRENT_TIMESTAMP, mysql only
+	Generated     string  // storage of a generated column, virtual or stored, empty if the column is not generated
+	Expression    string  // expression of a generated column
+}
+
+// IndexInfo an index of an existing table
+type IndexInfo struct {
+	Name    string
+	Columns []string // index columns in order
+	Type    string   // index type, e.g. btree, fulltext
+	Unique  bool     // is unique, primary key is unique
+	Primary bool     // is primary key
+}
+
+// ForeignKeyInfo a foreign key of an existing table
+type ForeignKeyInfo struct {
+	Name           string   // constraint name, empty on sqlite
+	Columns        []string // key columns in order
+	ForeignTable   string   // referenced table
+	ForeignColumns []string // referenced columns in order
+	OnUpdate       string   // on update rule, e.g. cascade, restrict, no action
+	OnDelete       string   // on delete rule, e.g. cascade, set null, no action
+}
+
+// CheckInfo a check constraint of an existing table
+type CheckInfo struct {
+	Name       string
+	Expression string // check expression as reported by the database
+}
+
+// GetTables get the tables of the database, ordered by name
+func (s *Schema) GetTables() ([]*TableInfo, error) {
+	if err := s.checkDatabase(); err != nil {
+		return nil, err
+	}
+
+	query, args := s.grammar.CompileTables(s.config.Database)
+
+	rows, err := s.config.DB.QueryContext(s.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []*TableInfo
+
+	for rows.Next() {
+		var (
+			table   = &TableInfo{}
+			comment sql.NullString
+		)
+
+		if err = rows.Scan(&table.Name, &comment); err != nil {
+			return nil, err
+		}
+
+		table.Comment = comment.String
+		tables = append(tables, table)
+	}
+
+	return tables, rows.Err()
+}
+
+// HasColumn check column exists
+func (s *Schema) HasColumn(table string, column string) (bool, error) {
+	return s.HasColumns(table, column)
+}
+
+// HasColumns check all the given columns exist
+func (s *Schema) HasColumns(table string, columns ...string) (bool, error) {
+	listing, err := s.GetColumnListing(table)
+	if err != nil {
+		return false, err
+	}
+
+	listing = arrMap(listing, strings.ToLower)
+	for _, column := range columns {
+		if !inArray(strings.ToLower(column), listing) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// GetColumnListing get the column names of table
+func (s *Schema) GetColumnListing(table string) ([]string, error) {
+	columns, err := s.GetColumns(table)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+
+	return names, nil
+}
+
+// GetColumnType get the data type of column, e.g. varchar
+func (s *Schema) GetColumnType(table string, column string) (string, error) {
+	columns, err := s.GetColumns(table)
+	if err != nil {
+		return "", err
+	}
+
+	for _, item := range columns {
+		if strings.EqualFold(item.Name, column) {
+			return item.TypeName, nil
+		}
+	}
+
+	return "", fmt.Errorf("schema err: column %s.%s not found", table, column)
+}
+
+// GetColumns get the columns of table, ordered by position
+func (s *Schema) GetColumns(table string) ([]*ColumnInfo, error) {
+	if err := s.checkDatabase(); err != nil {
+		return nil, err
+	}
+
+	query, args := s.grammar.CompileColumns(s.config.Database, s.config.Prefix+table)
+
+	rows, err := s.config.DB.QueryContext(s.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []*ColumnInfo
+
+	for rows.Next() {
+		var (
+			column                           = &ColumnInfo{}
+			length                           sql.NullInt64
+			def, charset, collation, comment sql.NullString
+			onUpdate, generated, expression  sql.NullString
+		)
+
+		err = rows.Scan(&column.Name, &column.TypeName, &column.Type, &length, &column.Nullable, &def,
+			&charset, &collation, &comment, &column.AutoIncrement, &column.Position, &onUpdate,
+			&generated, &expression)
+		if err != nil {
+			return nil, err
+		}
+
+		column.Length = length.Int64
+		column.Charset = charset.String
+		column.Collation = collation.String
+		column.Comment = comment.String
+		column.OnUpdate = onUpdate.String
+		column.Generated = generated.String
+		column.Expression = expression.String
+		if def.Valid {
+			// postgres appends the type cast to the literal, e.g. 'guest'::character varying
+			if _, ok := s.grammar.(*PostgresGrammar); ok {
+				def.String = postgresCast.ReplaceAllString(def.String, "$1")
+			}
+			column.Default = &def.String
+		}
+
+		columns = append(columns, column)
+	}
+
+	return columns, rows.Err()
+}
+
+// HasIndex check index exists, index is the index name string or the columns []string of the index
+func (s *Schema) HasIndex(table string, index interface{}) (bool, error) {
+	indexes, err := s.GetIndexes(table)
+	if err != nil {
+		return false, err
+	}
+
+	for _, item := range indexes {
+		switch value := index.(type) {
+		case string:
+			if strings.EqualFold(item.Name, value) {
+				return true, nil
+			}
+		case []string:
+			if strings.EqualFold(strings.Join(item.Columns, ","), strings.Join(value, ",")) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// GetIndexes get the indexes of table
+func (s *Schema) GetIndexes(table string) ([]*IndexInfo, error) {
+	if err := s.checkDatabase(); err != nil {
+		return nil, err
+	}
+
+	query, args := s.grammar.CompileIndexes(s.config.Database, s.config.Prefix+table)
+
+	rows, err := s.config.DB.QueryContext(s.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []*IndexInfo
+
+	for rows.Next() {
+		var (
+			index    = &IndexInfo{}
+			column   string
+			position int
+		)
+
+		if err = rows.Scan(&index.Name, &column, &index.Type, &index.Unique, &index.Primary, &position); err != nil {
+			return nil, err
+		}
+
+		// the rows of an index are ordered by position
+		if position > 1 && len(indexes) > 0 {
+			index = indexes[len(indexes)-1]
+		} else {
+			index.Type = strings.ToLower(index.Type)
+			indexes = append(indexes, index)
+		}
+		index.Columns = append(index.Columns, column)
+	}
+
+	return indexes, rows.Err()
+}
+
+// GetForeignKeys get the foreign keys of table
+func (s *Schema) GetForeignKeys(table string) ([]*ForeignKeyInfo, error) {
+	if err := s.checkDatabase(); err != nil {
+		return nil, err
+	}
+
+	query, args := s.grammar.CompileForeignKeys(s.config.Database, s.config.Prefix+table)
+
+	rows, err := s.config.DB.QueryContext(s.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var foreignKeys []*ForeignKeyInfo
+
+	for rows.Next() {
+		var (
+			foreignKey            = &ForeignKeyInfo{}
+			column, foreignColumn string
+			position              int
+		)
+
+		err = rows.Scan(&foreignKey.Name, &column, &foreignKey.ForeignTable, &foreignColumn,
+			&foreignKey.OnUpdate, &foreignKey.OnDelete, &position)
+		if err != nil {
+			return nil, err
+		}
+
+		// the rows of a foreign key are ordered by position
+		if position > 1 && len(foreignKeys) > 0 {
+			foreignKey = foreignKeys[len(foreignKeys)-1]
+		} else {
+			foreignKey.OnUpdate = strings.ToLower(foreignKey.OnUpdate)
+			foreignKey.OnDelete = strings.ToLower(foreignKey.OnDelete)
+			foreignKeys = append(foreignKeys, foreignKey)
+		}
+		foreignKey.Columns = append(foreignKey.Columns, column)
+		foreignKey.ForeignColumns = append(foreignKey.ForeignColumns, foreignColumn)
+	}
+
+	return foreignKeys, rows.Err()
+}
+
+// GetChecks get the check constraints of table, ordered by name, the sqlite checks without a name have an empty name
+func (s *Schema) GetChecks(table string) ([]*CheckInfo, error) {
+	if err := s.checkDatabase(); err != nil {
+		return nil, err
+	}
+
+	query, args := s.grammar.CompileChecks(s.config.Database, s.config.Prefix+table)
+
+	rows, err := s.config.DB.QueryContext(s.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// sqlite reports the create table statement, the check constraints are parsed from it
+	if parser, ok := s.grammar.(checkParser); ok {
+		var create string
+		for rows.Next() {
+			if err = rows.Scan(&create); err != nil {
+				return nil, err
+			}
+		}
+		return parser.parseChecks(create), rows.Err()
+	}
+
+	var checks []*CheckInfo
+
+	for rows.Next() {
+		check := &CheckInfo{}
+		if err = rows.Scan(&check.Name, &check.Expression); err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+
+	return checks, rows.Err()
+}
+
+// toColumn convert the column info to a blueprint column, the type is parsed from the full column type,
+// only the type keyword is lowercased, the enum values keep their case,
+// and the types unknown to the grammars keep the full column type
+func (c *ColumnInfo) toColumn() *Column {
+	var (
+		raw    = strings.TrimSpace(c.Type)
+		base   = strings.ToLower(raw)
+		params []string
+		attrs  = Map{
+			ColumnAttrNullable: c.Nullable,
+			ColumnAttrComment:  c.Comment,
+		}
+	)
+
+	if start, end := strings.Index(raw, "("), strings.LastIndex(raw, ")"); start > 0 && end > start {
+		base = strings.ToLower(strings.TrimSpace(raw[:start] + raw[end+1:]))
+		params = splitValues(raw[start+1 : end])
+	}
+
+	if strings.HasSuffix(base, " unsigned") {
+		attrs[ColumnAttrUnsigned] = true
+		base = strings.TrimSuffix(base, " unsigned")
+	}
+
+	if c.AutoIncrement {
+		attrs[ColumnAttrAutoIncrement] = true
+	} else if def, ok := parseDefault(c.Default); ok {
+		if expression, ok := def.(Expression); ok && currentTimestamp.MatchString(string(expression)) {
+			attrs[ColumnAttrUseCurrent] = true
+		} else {
+			attrs[ColumnAttrDefault] = def
+		}
+	}
+
+	if currentTimestamp.MatchString(c.OnUpdate) {
+		attrs[ColumnAttrUseCurrentOnUpdate] = true
+	}
+
+	switch c.Generated {
+	case "virtual":
+		attrs[ColumnAttrVirtualAs] = c.Expression
+	case "stored":
+		attrs[ColumnAttrStoredAs] = c.Expression
+	}
+
+	if c.Charset != "" {
+		attrs[ColumnAttrCharset] = c.Charset
+	}
+	if c.Collation != "" {
+		attrs[ColumnAttrCollate] = c.Collation
+	}
+
+	param := func(i int, def int) int {
+		if i < len(params) {
+			if n, err := strconv.Atoi(strings.TrimSpace(params[i])); err == nil {
+				return n
+			}
+		}
+		return def
+	}
+
+	var types string
+
+	switch base {
+	case "char", "character", "bpchar":
+		types = ColumnTypeChar
+		attrs[ColumnAttrLength] = param(0, int(ternary(c.Length > 0, c.Length, 1)))
+	case "varchar", "character varying", "nvarchar":
+		types = ColumnTypeVarchar
+		attrs[ColumnAttrLength] = param(0, int(ternary(c.Length > 0, c.Length, DefaultStringLength)))
+	case "tinyint":
+		types = ternary(param(0, 0) == 1, ColumnTypeBoolean, ColumnTypeTinyInt)
+	case "boolean", "bool":
+		types = ColumnTypeBoolean
+	case "smallint", "int2":
+		types = ColumnTypeSmallInt
+	case "mediumint":
+		types = ColumnTypeMediumInt
+	case "int", "integer", "int4":
+		types = ColumnTypeInt
+	case "bigint", "int8":
+		types = ColumnTypeBigInt
+	case "float", "real", "float4":
+		types = ColumnTypeFloat
+	case "double", "double precision", "float8":
+		types = ColumnTypeDouble
+	case "decimal", "numeric":
+		types = ColumnTypeDecimal
+	case "enum", "set":
+		types = base
+		attrs[ColumnAttrAllowed] = arrMap(params, unquote)
+	case "json", "jsonb":
+		types = ColumnTypeJson
+	case "date", "year", "tinytext", "text", "mediumtext", "longtext":
+		types = base
+	case "datetime":
+		types = ColumnTypeDateTime
+	case "timestamp", "timestamp without time zone", "timestamp with time zone":
+		types = ColumnTypeTimestamp
+	case "time", "time without time zone", "time with time zone":
+		types = ColumnTypeTime
+	case "uuid":
+		types = ColumnTypeUuid
+	case "binary", "varbinary":
+		types = base
+		if len(params) > 0 {
+			attrs[ColumnAttrLength] = param(0, 1)
+		}
+	case "tinyblob", "blob", "mediumblob", "longblob":
+		types = base
+	case "bytea":
+		types = ColumnTypeBinary
+	case "geometry":
+		// postgis reports the subtype and the srid, e.g. geometry(Point,4326)
+		types = ColumnTypeGeometry
+		if len(params) > 0 && inArray(strings.ToLower(params[0]), spatialTypes) {
+			types = strings.ToLower(params[0])
+		}
+		if len(params) > 1 {
+			attrs[ColumnAttrSrid] = param(1, 0)
+		}
+	case "geomcollection":
+		types = ColumnTypeGeometryCollection
+	case ColumnTypePoint, ColumnTypeLineString, ColumnTypePolygon, ColumnTypeMultiPoint, ColumnTypeMultiPolygon, ColumnTypeGeometryCollection:
+		types = base
+	default:
+		types = raw
+		delete(attrs, ColumnAttrUnsigned)
+	}
+
+	switch types {
+	case ColumnTypeFloat, ColumnTypeDouble, ColumnTypeDecimal:
+		// a float or double without the precision is kept without the total and places
+		if len(params) > 0 {
+			attrs[ColumnAttrTotal] = param(0, 8)
+			attrs[ColumnAttrPlaces] = param(1, 0)
+		}
+	case ColumnTypeDateTime, ColumnTypeTimestamp, ColumnTypeTime:
+		if precision := param(0, 0); precision > 0 {
+			attrs[ColumnAttrPrecision] = precision
+		}
+	}
+
+	return &Column{
+		Type:       types,
+		Name:       c.Name,
+		Attributes: attrs,
+	}
+}
+
+// expressionDefault match the default values reported by the database which are expressions,
+// postgres may append a type cast to the expression
+var expressionDefault = regexp.MustCompile(`(?i)^(\(.*\)|current_(timestamp|date|time)(\(\d*\))?|localtime(stamp)?(\(\d*\))?|\w+\(.*\))(::[a-z_][\w .,"\[\]()]*)?$`)
+
+// currentTimestamp match the current timestamp expressions reported by the database
+var currentTimestamp = regexp.MustCompile(`(?i)^(current_timestamp|now)(\(\d*\))?$`)
+
+// postgresCast match a literal with the type cast appended by postgres, e.g. 'a::b'::character varying, '-1'::integer
+var postgresCast = regexp.MustCompile(`(?is)^\s*('(?:[^']|'')*'|-?\d+(?:\.\d+)?|null|true|false)::[a-z_][\w .,"\[\]()]*$`)
+
+// parseDefault parse the default value reported by the database,
+// mysql reports the raw value, mariadb, sqlite and postgres quote strings,
+// the expressions such as CURRENT_TIMESTAMP are parsed as Expression
+func parseDefault(def *string) (interface{}, bool) {
+	if def == nil {
+		return nil, false
+	}
+
+	value := strings.TrimSpace(*def)
+
+	if strings.EqualFold(value, "null") || strings.HasPrefix(strings.ToLower(value), "nextval(") {
+		return nil, false
+	}
+
+	if expressionDefault.MatchString(value) {
+		return Raw(value), true
+	}
+
+	return unquote(value), true
+}
+
+// unquote remove the single quotes of a string literal
+func unquote(value string) string {
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return strings.Replace(value[1:len(value)-1], "''", "'", -1)
+	}
+	return value
+}
+
+// splitValues split the comma separated values of a column type, commas in quoted values are kept
+func splitValues(value string) (values []string) {
+	var (
+		quoted bool
+		start  int
+	)
+
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\'':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				values = append(values, strings.TrimSpace(value[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	return append(values, strings.TrimSpace(value[start:]))
+}
@@ -0,0 +1,291 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// testInspectSchema a schema answering the introspection queries with the given rows
+func testInspectSchema(database string, columns []string, values [][]driver.Value, failed error) *Schema {
+	db := &testDriver{}
+	db.onQuery = func(query string, args []driver.NamedValue) (driver.Rows, error) {
+		if failed != nil {
+			return nil, failed
+		}
+		if len(args) != 2 || args[0].Value != "app" || args[1].Value != "app_users" {
+			return nil, fmt.Errorf("unexpected args %v", args)
+		}
+		return &testRows{columns: columns, values: append([][]driver.Value(nil), values...)}, nil
+	}
+
+	return NewSchema(context.Background(), &Config{DB: sql.OpenDB(db), Database: database, Prefix: "app_"})
+}
+
+func TestSchema_GetColumns(t *testing.T) {
+	columns := []string{"name", "type_name", "type", "length", "nullable", "default", "charset", "collation",
+		"comment", "auto_increment", "position", "on_update", "generated", "expression"}
+	values := [][]driver.Value{
+		{"id", "bigint", "bigint unsigned", nil, int64(0), nil, nil, nil, "", int64(1), int64(1), nil, nil, nil},
+		{"name", "varchar", "varchar(50)", int64(50), int64(0), "", "utf8mb4", "utf8mb4_unicode_ci", "user name", int64(0), int64(2), nil, nil, nil},
+		{"bio", "text", "text", int64(65535), int64(1), nil, "utf8mb4", "utf8mb4_unicode_ci", "", int64(0), int64(3), nil, nil, nil},
+		{"updated_at", "timestamp", "timestamp", nil, int64(1), "CURRENT_TIMESTAMP", nil, nil, "", int64(0), int64(4), "CURRENT_TIMESTAMP", nil, nil},
+		{"label", "varchar", "varchar(80)", int64(80), int64(1), nil, "utf8mb4", "utf8mb4_unicode_ci", "", int64(0), int64(5), nil, "virtual", "concat(`name`, '!')"},
+	}
+
+	newSchema := testInspectSchema("app", columns, values, nil)
+	infos, err := newSchema.GetColumns("users")
+	if err != nil || len(infos) != 5 {
+		t.Fatal("GetColumns err:", err, infos)
+	}
+
+	id, name, bio, updatedAt, label := infos[0], infos[1], infos[2], infos[3], infos[4]
+	if id.Name != "id" || id.TypeName != "bigint" || id.Type != "bigint unsigned" || id.Length != 0 ||
+		id.Nullable || id.Default != nil || !id.AutoIncrement || id.Position != 1 {
+		t.Fatal("GetColumns err: id", *id)
+	}
+	if name.Length != 50 || name.Nullable || name.Default == nil || *name.Default != "" ||
+		name.Charset != "utf8mb4" || name.Collation != "utf8mb4_unicode_ci" || name.Comment != "user name" || name.AutoIncrement {
+		t.Fatal("GetColumns err: name", *name)
+	}
+	if !bio.Nullable || bio.Default != nil {
+		t.Fatal("GetColumns err: bio", *bio)
+	}
+	if updatedAt.Default == nil || *updatedAt.Default != "CURRENT_TIMESTAMP" || updatedAt.OnUpdate != "CURRENT_TIMESTAMP" {
+		t.Fatal("GetColumns err: updated_at", *updatedAt)
+	}
+	if label.Generated != "virtual" || label.Expression != "concat(`name`, '!')" || label.Position != 5 {
+		t.Fatal("GetColumns err: label", *label)
+	}
+
+	typeName, err := newSchema.GetColumnType("users", "Updated_At")
+	if err != nil || typeName != "timestamp" {
+		t.Fatal("GetColumnType err:", typeName, err)
+	}
+
+	if _, err = newSchema.GetColumnType("users", "missing"); err == nil || err.Error() != "schema err: column users.missing not found" {
+		t.Fatal("GetColumnType err: missing", err)
+	}
+
+	for _, item := range []struct {
+		columns []string
+		has     bool
+	}{
+		{[]string{"id"}, true},
+		{[]string{"ID", "name", "label"}, true},
+		{[]string{"id", "missing"}, false},
+	} {
+		has, err := newSchema.HasColumns("users", item.columns...)
+		if err != nil || has != item.has {
+			t.Fatal("HasColumns err:", item.columns, has, err)
+		}
+	}
+
+	if has, err := newSchema.HasColumn("users", "bio"); err != nil || !has {
+		t.Fatal("HasColumn err:", has, err)
+	}
+}
+
+func TestSchema_GetColumns_default(t *testing.T) {
+	columns := []string{"name", "type_name", "type", "length", "nullable", "default", "charset", "collation",
+		"comment", "auto_increment", "position", "on_update", "generated", "expression"}
+	row := func(def string) []driver.Value {
+		return []driver.Value{"value", "varchar", "varchar(50)", int64(50), int64(0), def, nil, nil, "", int64(0), int64(1), nil, nil, nil}
+	}
+
+	cases := []struct {
+		driver  string
+		def     string
+		current string      // the reported default without the postgres type cast
+		parsed  interface{} // the default of the blueprint column
+	}{
+		{DriverMysql, "a::b", "a::b", "a::b"},
+		{DriverMysql, "'a'::text", "'a'::text", "'a'::text"},
+		{DriverPostgres, "'a::b'::character varying", "'a::b'", "a::b"},
+		{DriverPostgres, "'it''s'::text", "'it''s'", "it's"},
+		{DriverPostgres, "'-1'::integer", "'-1'", "-1"},
+		{DriverPostgres, "10.5::numeric(10,2)", "10.5", "10.5"},
+		{DriverPostgres, "'{}'::jsonb", "'{}'", "{}"},
+		{DriverPostgres, "('now'::text)::date", "('now'::text)::date", Raw("('now'::text)::date")},
+		{DriverPostgres, "CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP", nil},
+	}
+
+	for _, item := range cases {
+		newSchema := testInspectSchema("app", columns, [][]driver.Value{row(item.def)}, nil)
+		newSchema.config.Driver = item.driver
+		newSchema.grammar = newGrammar(newSchema.config)
+
+		infos, err := newSchema.GetColumns("users")
+		if err != nil || len(infos) != 1 || infos[0].Default == nil || *infos[0].Default != item.current {
+			t.Fatal("GetColumns err: default", item.def, err, infos)
+		}
+		if def := infos[0].toColumn().Attributes[ColumnAttrDefault]; def != item.parsed {
+			t.Fatal("toColumn err: default", item.def, def)
+		}
+	}
+}
+
+func TestSchema_GetColumns_error(t *testing.T) {
+	failed := errors.New("connection refused")
+	cases := []struct {
+		name   string
+		schema *Schema
+		err    string
+	}{
+		{"Database", testInspectSchema("", nil, nil, nil), "schema err: config.Database is empty"},
+		{"Query", testInspectSchema("app", nil, nil, failed), "connection refused"},
+		{"Scan", testInspectSchema("app", []string{"name", "type_name"}, [][]driver.Value{{"id", "bigint"}}, nil), "sql: expected 2 destination arguments in Scan, not 14"},
+	}
+
+	for _, item := range cases {
+		if _, err := item.schema.GetColumns("users"); err == nil || !strings.Contains(err.Error(), item.err) {
+			t.Fatal("GetColumns err:", item.name, err)
+		}
+		if _, err := item.schema.GetColumnType("users", "id"); err == nil || !strings.Contains(err.Error(), item.err) {
+			t.Fatal("GetColumnType err:", item.name, err)
+		}
+		if has, err := item.schema.HasColumns("users", "id"); has || err == nil || !strings.Contains(err.Error(), item.err) {
+			t.Fatal("HasColumns err:", item.name, has, err)
+		}
+	}
+}
+
+func TestSchema_inspectWithoutDB(t *testing.T) {
+	newSchema := NewSchema(context.Background(), &Config{Database: "app"})
+	inspects := map[string]func() error{
+		"HasTable":       func() error { _, err := newSchema.HasTable("users"); return err },
+		"GetTables":      func() error { _, err := newSchema.GetTables(); return err },
+		"GetColumns":     func() error { _, err := newSchema.GetColumns("users"); return err },
+		"GetIndexes":     func() error { _, err := newSchema.GetIndexes("users"); return err },
+		"GetForeignKeys": func() error { _, err := newSchema.GetForeignKeys("users"); return err },
+		"GetChecks":      func() error { _, err := newSchema.GetChecks("users"); return err },
+		"Generate":       func() error { return newSchema.Generate(io.Discard) },
+		"Sync": func() error {
+			_, err := newSchema.Pretend(func(s *Schema) error {
+				return s.Sync("users", func(table *Blueprint) { table.Id() })
+			})
+			return err
+		},
+	}
+
+	for name, inspect := range inspects {
+		if err := inspect(); err == nil || err.Error() != "DB is nil" {
+			t.Fatal("inspect without DB err:", name, err)
+		}
+	}
+}
+
+func TestSchema_GetIndexes(t *testing.T) {
+	columns := []string{"name", "column", "type", "unique", "primary", "position"}
+	values := [][]driver.Value{
+		{"PRIMARY", "id", "BTREE", int64(1), int64(1), int64(1)},
+		{"users_body_fulltext", "body", "FULLTEXT", int64(0), int64(0), int64(1)},
+		{"users_name_age_unique", "name", "BTREE", int64(1), int64(0), int64(1)},
+		{"users_name_age_unique", "age", "BTREE", int64(1), int64(0), int64(2)},
+		{"users_team_id_role_created_at_index", "team_id", "BTREE", int64(0), int64(0), int64(1)},
+		{"users_team_id_role_created_at_index", "role", "BTREE", int64(0), int64(0), int64(2)},
+		{"users_team_id_role_created_at_index", "created_at", "BTREE", int64(0), int64(0), int64(3)},
+	}
+
+	newSchema := testInspectSchema("app", columns, values, nil)
+	indexes, err := newSchema.GetIndexes("users")
+	if err != nil {
+		t.Fatal("GetIndexes err:", err)
+	}
+
+	expected := []IndexInfo{
+		{Name: "PRIMARY", Columns: []string{"id"}, Type: "btree", Unique: true, Primary: true},
+		{Name: "users_body_fulltext", Columns: []string{"body"}, Type: "fulltext"},
+		{Name: "users_name_age_unique", Columns: []string{"name", "age"}, Type: "btree", Unique: true},
+		{Name: "users_team_id_role_created_at_index", Columns: []string{"team_id", "role", "created_at"}, Type: "btree"},
+	}
+	if len(indexes) != len(expected) {
+		t.Fatal("GetIndexes err:", indexes)
+	}
+	for i, index := range indexes {
+		if fmt.Sprint(*index) != fmt.Sprint(expected[i]) {
+			t.Fatal("GetIndexes err:", "\nexpected:", expected[i], "\ngot:", *index)
+		}
+	}
+
+	for _, item := range []struct {
+		index interface{}
+		has   bool
+	}{
+		{"users_name_age_unique", true},
+		{[]string{"team_id", "role", "created_at"}, true},
+		{[]string{"role", "team_id", "created_at"}, false},
+		{[]string{"name"}, false},
+	} {
+		if has, err := newSchema.HasIndex("users", item.index); err != nil || has != item.has {
+			t.Fatal("HasIndex err:", item.index, has, err)
+		}
+	}
+}
+
+func TestSchema_GetForeignKeys(t *testing.T) {
+	columns := []string{"name", "column", "foreign_table", "foreign_column", "on_update", "on_delete", "position"}
+	values := [][]driver.Value{
+		{"posts_author_id_team_id_foreign", "author_id", "app_members", "user_id", "NO ACTION", "CASCADE", int64(1)},
+		{"posts_author_id_team_id_foreign", "team_id", "app_members", "team_id", "NO ACTION", "CASCADE", int64(2)},
+		{"posts_category_id_foreign", "category_id", "app_categories", "id", "CASCADE", "SET NULL", int64(1)},
+	}
+
+	foreignKeys, err := testInspectSchema("app", columns, values, nil).GetForeignKeys("users")
+	if err != nil {
+		t.Fatal("GetForeignKeys err:", err)
+	}
+
+	expected := []ForeignKeyInfo{
+		{Name: "posts_author_id_team_id_foreign", Columns: []string{"author_id", "team_id"}, ForeignTable: "app_members",
+			ForeignColumns: []string{"user_id", "team_id"}, OnUpdate: "no action", OnDelete: "cascade"},
+		{Name: "posts_category_id_foreign", Columns: []string{"category_id"}, ForeignTable: "app_categories",
+			ForeignColumns: []string{"id"}, OnUpdate: "cascade", OnDelete: "set null"},
+	}
+	if len(foreignKeys) != len(expected) {
+		t.Fatal("GetForeignKeys err:", foreignKeys)
+	}
+	for i, foreignKey := range foreignKeys {
+		if fmt.Sprint(*foreignKey) != fmt.Sprint(expected[i]) {
+			t.Fatal("GetForeignKeys err:", "\nexpected:", expected[i], "\ngot:", *foreignKey)
+		}
+	}
+}
+
+func TestColumnInfo_toColumn(t *testing.T) {
+	grammar := &MysqlGrammar{}
+	cases := []struct {
+		types    string
+		column   string // the column type
+		sql      string // the column type compiled by the mysql grammar
+		unsigned bool
+	}{
+		{"enum('Draft','Published')", ColumnTypeEnum, "enum('Draft', 'Published')", false},
+		{"SET('a,b','C')", ColumnTypeSet, "set('a,b', 'C')", false},
+		{"blob", ColumnTypeBlob, "blob", false},
+		{"longblob", ColumnTypeLongBlob, "longblob", false},
+		{"varbinary(16)", ColumnTypeVarBinary, "varbinary(16)", false},
+		{"binary(16)", ColumnTypeBinary, "binary(16)", false},
+		{"double", ColumnTypeDouble, "double", false},
+		{"float", ColumnTypeFloat, "float", false},
+		{"double(10,3)", ColumnTypeDouble, "double(10, 3)", false},
+		{"decimal(10,2) unsigned", ColumnTypeDecimal, "decimal(10, 2)", true},
+		{"int(11) unsigned", ColumnTypeInt, "int", true},
+		{"tinyint(1)", ColumnTypeBoolean, "tinyint(1)", false},
+		{"datetime(3)", ColumnTypeDateTime, "datetime(3)", false},
+		{"geometry(Point,4326)", ColumnTypePoint, "point srid 4326", false},
+		{"bit(1)", "bit(1)", "bit(1)", false},
+	}
+
+	for _, item := range cases {
+		column := (&ColumnInfo{Name: "value", Type: item.types}).toColumn()
+		if column.Type != item.column || grammar.GetType(column) != item.sql || (column.Attributes[ColumnAttrUnsigned] == true) != item.unsigned {
+			t.Fatal("toColumn err:", item.types, "\ntype:", column.Type, "\nsql:", grammar.GetType(column), "\nattributes:", column.Attributes)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSchema_Pretend(t *testing.T) {
+	newSchema := NewSchema(context.Background(), &Config{})
+
+	sql, err := newSchema.Pretend(func(s *Schema) error {
+		if err := s.Create("users", func(table *Blueprint) {
+			table.Id()
+			table.String("name", 20).Unique()
+		}); err != nil {
+			return err
+		}
+
+		if err := s.Table("users", func(table *Blueprint) {
+			table.Int("age").Nullable()
+		}); err != nil {
+			return err
+		}
+
+		return s.Rename("users", "members")
+	})
+	if err != nil {
+		t.Fatal("Pretend err:", err)
+	}
+
+	expected := []string{
+		"create table `users` (`id` bigint unsigned not null auto_increment primary key, `name` varchar(20) not null) default character set utf8mb4 collate 'utf8mb4_unicode_ci' engine = InnoDB",
+		"alter table `users` add unique `users_name_unique`(`name`)",
+		"alter table `users` add `age` int null",
+		"rename table `users` to `members`",
+	}
+	if len(sql) != len(expected) {
+		t.Fatal("Pretend err:", "\nsql:", expected, "\ngen:", sql)
+	}
+	for i, s := range expected {
+		if sql[i] != s {
+			t.Fatal("Pretend err:", "\nsql:", expected, "\ngen:", sql)
+		}
+	}
+
+	failed := errors.New("failed")
+	sql, err = newSchema.Pretend(func(s *Schema) error {
+		_ = s.Drop("users")
+		return failed
+	})
+	if err != failed || len(sql) != 1 || sql[0] != "drop table `users`" {
+		t.Fatal("Pretend err:", err, sql)
+	}
+
+	if err = newSchema.Drop("users"); err == nil {
+		t.Fatal("Pretend err: the schema is pretending after Pretend")
+	}
+}
+
+func TestSchema_Plan(t *testing.T) {
+	newSchema := NewSchema(context.Background(), &Config{})
+
+	plan, err := newSchema.Plan(func(s *Schema) error {
+		if err := s.Table("users", func(table *Blueprint) {
+			table.Int("age").Nullable()
+			table.DropColumn("nick", "avatar")
+			table.RenameColumn("name", "username")
+		}); err != nil {
+			return err
+		}
+
+		return s.DropIfExists("posts")
+	})
+	if err != nil {
+		t.Fatal("Plan err:", err)
+	}
+
+	expected := []*Statement{
+		{Table: "users", Command: commandDropColumn, Destructive: true, Columns: []string{"nick", "avatar"}, SQL: "alter table `users` drop `nick`, drop `avatar`"},
+		{Table: "users", Command: commandRenameColumn, Columns: []string{"name", "username"}, SQL: "alter table `users` rename column `name` to `username`"},
+		{Table: "users", Command: commandAdd, Columns: []string{"age"}, SQL: "alter table `users` add `age` int null"},
+		{Table: "posts", Command: commandDropIfExists, Destructive: true, SQL: "drop table if exists `posts`"},
+	}
+	if len(plan.Statements) != len(expected) {
+		t.Fatal("Plan err:", "\nexpected:", len(expected), "\ngen:", len(plan.Statements))
+	}
+	for i, statement := range expected {
+		if fmt.Sprint(plan.Statements[i]) != fmt.Sprint(statement) {
+			t.Fatal("Plan err:", "\nexpected:", statement, "\ngen:", plan.Statements[i])
+		}
+	}
+
+	if destructive := plan.Destructive(); len(destructive) != 2 {
+		t.Fatal("Plan err: destructive", destructive)
+	}
+
+	rejected := errors.New("rejected")
+	err = plan.Apply(context.Background(), func(statements []*Statement) ([]*Statement, error) {
+		return nil, rejected
+	})
+	if err != rejected {
+		t.Fatal("Apply err:", err)
+	}
+}
+
+func TestBlueprint_destructive(t *testing.T) {
+	type destructiveCase struct {
+		name        string
+		current     string
+		blueprint   func(table *Blueprint)
+		destructive bool
+	}
+
+	cases := []destructiveCase{
+		{"Int_BigInt", "int", func(table *Blueprint) { table.BigInt("value").Change() }, false},
+		{"BigInt_Int", "bigint", func(table *Blueprint) { table.Int("value").Change() }, true},
+		{"Int_Unsigned", "int", func(table *Blueprint) { table.UnsignedInt("value").Change() }, true},
+		{"String_Longer", "varchar(50)", func(table *Blueprint) { table.String("value", 100).Change() }, false},
+		{"String_Shorter", "varchar(100)", func(table *Blueprint) { table.String("value", 50).Change() }, true},
+		{"Text_String", "text", func(table *Blueprint) { table.String("value").Change() }, true},
+		{"Decimal_Places", "decimal(10,2)", func(table *Blueprint) { table.Decimal("value", 10, 1).Change() }, true},
+		{"Enum_Added", "enum('a','b')", func(table *Blueprint) { table.Enum("value", []string{"a", "b", "c"}).Change() }, false},
+		{"Enum_Removed", "enum('a','b')", func(table *Blueprint) { table.Enum("value", []string{"a"}).Change() }, true},
+		{"String_Int", "varchar(255)", func(table *Blueprint) { table.Int("value").Change() }, true},
+	}
+
+	newSchema := NewSchema(context.Background(), &Config{})
+
+	for _, item := range cases {
+		blueprint := NewBlueprint(newSchema, "users", item.blueprint)
+		blueprint.currentColumns = []*ColumnInfo{{Name: "value", Type: item.current}}
+
+		statements, err := blueprint.toStatements(newSchema.GetGrammar())
+		if err != nil || len(statements) != 1 || statements[0].Destructive != item.destructive {
+			t.Fatal("destructive err:", item.name, statements)
+		}
+	}
+}
+
+// testDriver a database driver which records the executed statements, and fails the statements in failed,
+// the queries are answered by onQuery
+type testDriver struct {
+	executed []string
+	failed   map[string]error
+	onExec   func(query string, args []driver.NamedValue)
+	onQuery  func(query string, args []driver.NamedValue) (driver.Rows, error)
+}
+
+func (d *testDriver) Open(string) (driver.Conn, error) { return d, nil }
+func (d *testDriver) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+func (d *testDriver) Close() error              { return nil }
+func (d *testDriver) Begin() (driver.Tx, error) { return nil, errors.New("begin is not supported") }
+func (d *testDriver) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	d.executed = append(d.executed, query)
+	if err := d.failed[query]; err != nil {
+		return nil, err
+	}
+	if d.onExec != nil {
+		d.onExec(query, args)
+	}
+	return driver.RowsAffected(0), nil
+}
+func (d *testDriver) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if d.onQuery == nil {
+		return nil, errors.New("query is not supported")
+	}
+	return d.onQuery(query, args)
+}
+
+func (d *testDriver) Connect(context.Context) (driver.Conn, error) { return d, nil }
+func (d *testDriver) Driver() driver.Driver                        { return d }
+
+// testRows the rows of a query answered by testDriver
+type testRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *testRows) Columns() []string { return r.columns }
+func (r *testRows) Close() error      { return nil }
+func (r *testRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func TestSchema_exec(t *testing.T) {
+	var (
+		failed = errors.New("failed")
+		db     = &testDriver{failed: map[string]error{"alter table `users` drop `nick`": failed}}
+		events []string
+		logs   bytes.Buffer
+	)
+
+	newSchema := NewSchema(context.Background(), &Config{
+		DB: sql.OpenDB(db),
+		BeforeStatement: func(ctx context.Context, statement *Statement) {
+			events = append(events, "before "+statement.Command)
+		},
+		AfterStatement: func(ctx context.Context, statement *Statement, elapsed time.Duration, err error) {
+			events = append(events, fmt.Sprint("after ", statement.Command, " ", err))
+		},
+		OnError: func(ctx context.Context, statement *Statement, err error) {
+			events = append(events, "error "+statement.Command)
+		},
+		Logger: slog.New(slog.NewTextHandler(&logs, nil)),
+	})
+
+	if err := newSchema.Table("users", func(table *Blueprint) {
+		table.Int("age")
+	}); err != nil {
+		t.Fatal("exec err:", err)
+	}
+
+	if err := newSchema.DropColumns("users", "nick"); !errors.Is(err, failed) {
+		t.Fatal("exec err:", err)
+	}
+
+	expected := []string{"before add", "after add <nil>", "before dropColumn", "after dropColumn failed", "error dropColumn"}
+	if fmt.Sprint(events) != fmt.Sprint(expected) {
+		t.Fatal("exec err:", "\nexpected:", expected, "\nevents:", events)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 ||
+		!strings.Contains(lines[0], "level=INFO") || !strings.Contains(lines[0], "table=users command=add elapsed=") ||
+		!strings.Contains(lines[1], "level=ERROR") || !strings.Contains(lines[1], "command=dropColumn") || !strings.Contains(lines[1], "error=failed") {
+		t.Fatal("exec err: logs", logs.String())
+	}
+}
+
+// testMysqlError an error like github.com/go-sql-driver/mysql.MySQLError
+type testMysqlError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *testMysqlError) Error() string {
+	return e.Message
+}
+
+func TestStatementError(t *testing.T) {
+	db := &testDriver{failed: map[string]error{
+		"alter table `users` add `age` int not null":             &testMysqlError{Number: 1060, Message: "Duplicate column name 'age'"},
+		"alter table `users` add index `users_age_index`(`age`)": fmt.Errorf("exec: %w", errors.New("Error 1061 (42000): Duplicate key name 'users_age_index'")),
+		"drop table `posts`": errors.New("no such table"),
+	}}
+
+	newSchema := NewSchema(context.Background(), &Config{DB: sql.OpenDB(db)})
+
+	type errorCase struct {
+		name      string
+		blueprint func(table *Blueprint)
+		index     int
+		command   string
+		sentinel  error
+	}
+
+	cases := []errorCase{
+		{"Duplicate_Column", func(table *Blueprint) { table.Int("age") }, 0, commandAdd, ErrDuplicateColumn},
+		{"Duplicate_Key", func(table *Blueprint) { table.Index("age") }, 0, commandIndex, ErrDuplicateKeyName},
+		{"Second_Statement", func(table *Blueprint) { table.DropColumn("nick"); table.Index("age") }, 1, commandIndex, ErrDuplicateKeyName},
+	}
+
+	for _, item := range cases {
+		err := newSchema.Table("users", item.blueprint)
+
+		var statementError *StatementError
+		if !errors.As(err, &statementError) {
+			t.Fatal("StatementError err:", item.name, err)
+		}
+		if statementError.Index != item.index || statementError.Table != "users" || statementError.Command != item.command {
+			t.Fatal("StatementError err:", item.name, statementError)
+		}
+		if !errors.Is(err, item.sentinel) || errors.Is(err, ErrTableExists) {
+			t.Fatal("StatementError err:", item.name, "sentinel", err)
+		}
+	}
+
+	err := newSchema.Drop("posts")
+	if errors.Is(err, ErrNoSuchTable) || err.(*StatementError).Code != 0 {
+		t.Fatal("StatementError err: not a mysql error", err)
+	}
+}
+
+func TestSchema_checkDefaults(t *testing.T) {
+	newSchema := NewSchema(context.Background(), &Config{})
+
+	_, err := newSchema.Pretend(func(s *Schema) error {
+		return s.Table("users", func(table *Blueprint) {
+			table.Text("bio").Default("")
+		})
+	})
+	if err == nil || err.Error() != "schema err: text column users.bio can not have a default value" {
+		t.Fatal("checkDefaults err:", err)
+	}
+
+	_, err = newSchema.Pretend(func(s *Schema) error {
+		return s.Table("stores", func(table *Blueprint) {
+			table.Point("location").Default("POINT(0 0)")
+		})
+	})
+	if err == nil || err.Error() != "schema err: point column stores.location can not have a default value" {
+		t.Fatal("checkDefaults err:", err)
+	}
+
+	_, err = newSchema.Pretend(func(s *Schema) error {
+		return s.Table("files", func(table *Blueprint) {
+			table.LongBlob("data").Default("")
+		})
+	})
+	if err == nil || err.Error() != "schema err: longblob column files.data can not have a default value" {
+		t.Fatal("checkDefaults err:", err)
+	}
+
+	_, err = NewBlueprint(newSchema, "posts", func(table *Blueprint) {
+		table.Json("meta").Default("{}")
+	}).ToSql(newSchema.GetGrammar())
+	if err == nil || err.Error() != "schema err: json column posts.meta can not have a default value" {
+		t.Fatal("checkDefaults err: ToSql", err)
+	}
+
+	_, err = newSchema.Pretend(func(s *Schema) error {
+		return s.Table("users", func(table *Blueprint) {
+			table.Text("bio").Default(Raw("('')"))
+			table.Json("data").Nullable().Default(nil).Change()
+		})
+	})
+	if err != nil {
+		t.Fatal("checkDefaults err:", err)
+	}
+
+	sql, err := NewBlueprint(newSchema, "users", func(table *Blueprint) {
+		table.Binary("hash", 16).Default("x")
+	}).ToSql(newSchema.GetGrammar())
+	if err != nil || len(sql) != 1 || sql[0] != "alter table `users` add `hash` binary(16) not null default 'x'" {
+		t.Fatal("checkDefaults err: binary", sql, err)
+	}
+}
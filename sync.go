@@ -0,0 +1,391 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SyncOption options of Schema.Sync, nothing is dropped by default
+type SyncOption struct {
+	DropColumns bool // drop the columns of the table that are not in the blueprint
+	DropIndexes bool // drop the indexes of the table that are not in the blueprint, and recreate the changed indexes
+}
+
+// Sync Create the table or alter it to the complete definition of the callback,
+// only the added, changed and dropped columns and indexes are compiled.
+// The missing foreign keys are added, the missing and changed checks are added, the table comment is always set.
+func (s *Schema) Sync(table string, callback func(table *Blueprint), option ...SyncOption) error {
+	blueprint := NewBlueprint(s, table, callback)
+
+	exists, err := s.HasTable(table)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		blueprint.commands = append([]*Command{blueprint.createCommand(commandCreate)}, blueprint.commands...)
+		return s.build(blueprint)
+	}
+
+	columns, err := s.GetColumns(table)
+	if err != nil {
+		return err
+	}
+
+	indexes, err := s.GetIndexes(table)
+	if err != nil {
+		return err
+	}
+
+	foreignKeys, err := s.GetForeignKeys(table)
+	if err != nil {
+		return err
+	}
+
+	// the checks are inspected only when the blueprint has checks, mysql before 8.0.16 can not list them
+	var checks []*CheckInfo
+	if blueprint.addFluentChecks(); blueprint.hasCommand(commandCheck) {
+		if checks, err = s.GetChecks(table); err != nil {
+			return err
+		}
+	}
+
+	if err = s.diff(blueprint, columns, indexes, foreignKeys, checks, varDef(option)); err != nil {
+		return err
+	}
+
+	if len(blueprint.commands) == 0 {
+		return nil
+	}
+
+	return s.build(blueprint)
+}
+
+// diff Reduce the blueprint to the differences from the current table,
+// the commands are ordered: drop indexes, drop the recreated columns, add, change, drop columns, then the other commands.
+func (s *Schema) diff(blueprint *Blueprint, columns []*ColumnInfo, indexes []*IndexInfo, foreignKeys []*ForeignKeyInfo,
+	checks []*CheckInfo, option SyncOption) error {
+	blueprint.addFluentIndexes()
+	blueprint.addFluentChecks()
+
+	var (
+		drops     []*Command
+		others    []*Command
+		keeps     []*Column
+		desired   []string
+		dropping  []string
+		recreated []string // the columns dropped and added again
+		used      = map[*IndexInfo]bool{}
+	)
+
+	// columns
+	for _, column := range blueprint.columns {
+		desired = append(desired, strings.ToLower(column.Name))
+
+		current := findColumn(columns, column.Name)
+		if current == nil {
+			keeps = append(keeps, column)
+			continue
+		}
+
+		_, storage := column.generated()
+		if changed := s.columnChanged(blueprint, column, current); changed && s.regenerated(storage, current.Generated) {
+			// the column is added again by the complete definition
+			recreated = append(recreated, column.Name)
+			keeps = append(keeps, column)
+		} else if changed {
+			// the definition is complete, the current attributes are not kept
+			for key, value := range map[string]interface{}{
+				ColumnAttrNullable: false, ColumnAttrComment: "", ColumnAttrDefault: nil,
+				ColumnAttrUseCurrent: false, ColumnAttrUseCurrentOnUpdate: false,
+			} {
+				if _, ok := column.Attributes[key]; !ok {
+					column.Attributes[key] = value
+				}
+			}
+			column.Attributes[ColumnAttrChange] = true
+			keeps = append(keeps, column)
+		}
+
+		// the primary key of an auto increment column
+		if column.Attributes[ColumnAttrAutoIncrement] == true {
+			for _, index := range indexes {
+				if index.Primary {
+					used[index] = true
+				}
+			}
+		}
+	}
+
+	for _, column := range columns {
+		if !inArray(strings.ToLower(column.Name), desired) {
+			dropping = append(dropping, column.Name)
+		}
+	}
+
+	// indexes
+	for _, command := range blueprint.commands {
+		switch command.Name {
+		case commandPrimary, commandUnique, commandIndex, commandFulltext, commandSpatialIndex:
+			var (
+				name    = command.Attributes[commandAttrIndex].(string)
+				cols    = command.Attributes[commandAttrColumns].([]string)
+				primary = command.Name == commandPrimary
+				unique  = command.Name == commandPrimary || command.Name == commandUnique
+				kind    = map[string]string{commandFulltext: "fulltext", commandSpatialIndex: "spatial"}[command.Name]
+				current = findIndex(indexes, name, cols, primary, unique)
+			)
+
+			if current == nil {
+				others = append(others, command)
+				continue
+			}
+
+			used[current] = true
+			if sameIndex(current, cols, primary, unique) &&
+				kind == ternary(current.Type == "fulltext" || current.Type == "spatial", current.Type, "") {
+				continue
+			}
+
+			if !option.DropIndexes {
+				return fmt.Errorf("schema err: sync index %s of %s is changed, set DropIndexes to recreate it", current.Name, blueprint.GetTable())
+			}
+
+			drops = append(drops, dropIndex(blueprint, current))
+			others = append(others, command)
+		case commandForeign:
+			if findForeignKey(foreignKeys, command) == nil {
+				others = append(others, command)
+			}
+		case commandCheck:
+			current := findCheck(checks, command.Attributes[commandAttrIndex].(string))
+			if current == nil {
+				others = append(others, command)
+				continue
+			}
+
+			// the changed check is dropped and added again
+			if !sameExpression(current.Expression, command.Attributes[commandAttrExpression].(string)) {
+				others = append(others, blueprint.createCommand(commandDropCheck, Map{commandAttrIndex: current.Name}), command)
+			}
+		default:
+			others = append(others, command)
+		}
+	}
+
+	if option.DropIndexes {
+		for _, index := range indexes {
+			if used[index] || backsForeignKey(index, foreignKeys) || strings.HasPrefix(index.Name, "sqlite_autoindex_") {
+				continue
+			}
+
+			// the index is dropped with its columns
+			if option.DropColumns && len(filter(index.Columns, func(v string) bool {
+				return findColumn(columns, v) != nil && !inArray(strings.ToLower(v), desired)
+			})) == len(index.Columns) {
+				continue
+			}
+
+			drops = append(drops, dropIndex(blueprint, index))
+		}
+	}
+
+	if len(recreated) > 0 {
+		drops = append(drops, blueprint.createCommand(commandDropColumn, Map{commandAttrColumns: recreated}))
+	}
+
+	blueprint.columns = keeps
+	blueprint.commands = drops
+	blueprint.currentColumns = columns
+
+	if len(blueprint.getAddedColumns()) > 0 {
+		blueprint.addCommand(commandAdd)
+	}
+
+	if len(blueprint.getChangedColumns()) > 0 {
+		blueprint.addCommand(commandChange)
+	}
+
+	if option.DropColumns && len(dropping) > 0 {
+		blueprint.DropColumn(dropping...)
+	}
+
+	blueprint.commands = append(blueprint.commands, others...)
+
+	return nil
+}
+
+// columnChanged check the column differs from the current column,
+// both columns are compiled as changed columns by the grammar and compared
+func (s *Schema) columnChanged(blueprint *Blueprint, column *Column, current *ColumnInfo) bool {
+	var (
+		want = &Column{Type: column.Type, Name: column.Name, Attributes: Map{}}
+		have = current.toColumn()
+	)
+
+	for key, value := range column.Attributes {
+		want.Attributes[key] = value
+	}
+	want.Attributes[ColumnAttrChange] = true
+	have.Attributes[ColumnAttrChange] = true
+
+	// the position is not compared
+	delete(want.Attributes, ColumnAttrAfter)
+	delete(want.Attributes, ColumnAttrFirst)
+
+	// mysql does not report the srid in the column type
+	if _, ok := have.srid(); !ok {
+		delete(want.Attributes, ColumnAttrSrid)
+	}
+
+	// the charset and collation are compared when they are given
+	for _, key := range []string{ColumnAttrCharset, ColumnAttrCollate} {
+		if _, ok := want.Attributes[key]; !ok {
+			delete(have.Attributes, key)
+		}
+	}
+
+	// the database normalizes the generated expressions, only the storages are compared
+	if expression, storage := want.generated(); storage != "" {
+		if _, current := have.generated(); current == storage {
+			have.Attributes[ternary(storage == "stored", ColumnAttrStoredAs, ColumnAttrVirtualAs)] = expression
+		}
+	}
+
+	compiled := func(column *Column) string {
+		return strings.Join(s.grammar.GetChangeColumns(&Blueprint{
+			Prefix:  blueprint.Prefix,
+			table:   blueprint.table,
+			columns: []*Column{column},
+			config:  blueprint.config,
+		}), ", ")
+	}
+
+	if compiled(want) != compiled(have) {
+		return true
+	}
+
+	// sqlite has no column comments
+	if _, ok := s.grammar.(*SqliteGrammar); ok {
+		return false
+	}
+
+	comment, _ := want.Attributes[ColumnAttrComment].(string)
+
+	return comment != current.Comment
+}
+
+// regenerated check the column must be dropped and added again to change the generated storage,
+// mysql can not convert a virtual generated column to or from the other columns
+func (s *Schema) regenerated(storage, current string) bool {
+	if _, ok := s.grammar.(*MysqlGrammar); !ok {
+		return false
+	}
+	return storage != current && (storage == "virtual" || current == "virtual")
+}
+
+// findColumn find the column info by name
+func findColumn(columns []*ColumnInfo, name string) *ColumnInfo {
+	for _, column := range columns {
+		if strings.EqualFold(column.Name, name) {
+			return column
+		}
+	}
+	return nil
+}
+
+// findIndex find the current index of an index command, by the primary key, the name,
+// or an index of the same columns and uniqueness
+func findIndex(indexes []*IndexInfo, name string, columns []string, primary, unique bool) *IndexInfo {
+	for _, index := range indexes {
+		if (primary && index.Primary) || (!primary && !index.Primary && strings.EqualFold(index.Name, name)) {
+			return index
+		}
+	}
+
+	for _, index := range indexes {
+		if sameIndex(index, columns, primary, unique) {
+			return index
+		}
+	}
+
+	return nil
+}
+
+// sameIndex check the index has the columns and uniqueness
+func sameIndex(index *IndexInfo, columns []string, primary, unique bool) bool {
+	return index.Primary == primary && index.Unique == unique &&
+		strings.EqualFold(strings.Join(index.Columns, ","), strings.Join(columns, ","))
+}
+
+// findForeignKey find the current foreign key of a foreign command, by the name or the columns and referenced table
+func findForeignKey(foreignKeys []*ForeignKeyInfo, command *Command) *ForeignKeyInfo {
+	var (
+		name    = command.Attributes[commandAttrIndex].(string)
+		columns = strings.Join(command.Attributes[commandAttrColumns].([]string), ",")
+		on, _   = command.Attributes[commandAttrOn].(string)
+	)
+
+	for _, foreignKey := range foreignKeys {
+		if foreignKey.Name != "" && strings.EqualFold(foreignKey.Name, name) {
+			return foreignKey
+		}
+		if strings.EqualFold(strings.Join(foreignKey.Columns, ","), columns) && strings.HasSuffix(foreignKey.ForeignTable, on) {
+			return foreignKey
+		}
+	}
+
+	return nil
+}
+
+// findCheck find the current check constraint by name
+func findCheck(checks []*CheckInfo, name string) *CheckInfo {
+	for _, check := range checks {
+		if check.Name != "" && strings.EqualFold(check.Name, name) {
+			return check
+		}
+	}
+	return nil
+}
+
+var (
+	// checkIntroducer match the charset introducer of a string which mysql adds to the check expression, e.g. _utf8mb4'a'
+	checkIntroducer = regexp.MustCompile(`(?i)_[a-z0-9]+'`)
+	// checkNoise match the quotes of identifiers, the parentheses and the spaces which the database adds or removes
+	checkNoise = regexp.MustCompile("[`\"()\\s]")
+)
+
+// sameExpression check the check expression reported by the database is the expression of the check command
+func sameExpression(current string, expression string) bool {
+	normalize := func(expression string) string {
+		return checkNoise.ReplaceAllString(checkIntroducer.ReplaceAllString(strings.ToLower(expression), "'"), "")
+	}
+	return normalize(current) == normalize(expression)
+}
+
+// backsForeignKey check the index is created by the database for a foreign key
+func backsForeignKey(index *IndexInfo, foreignKeys []*ForeignKeyInfo) bool {
+	for _, foreignKey := range foreignKeys {
+		if strings.EqualFold(index.Name, foreignKey.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// dropIndex create the drop command of the current index
+func dropIndex(blueprint *Blueprint, index *IndexInfo) *Command {
+	switch {
+	case index.Primary:
+		return blueprint.createCommand(commandDropPrimary, Map{commandAttrIndex: index.Name})
+	case index.Unique:
+		return blueprint.createCommand(commandDropUnique, Map{commandAttrIndex: index.Name})
+	case index.Type == "fulltext":
+		return blueprint.createCommand(commandDropFulltext, Map{commandAttrIndex: index.Name})
+	case index.Type == "spatial":
+		return blueprint.createCommand(commandDropSpatialIndex, Map{commandAttrIndex: index.Name})
+	default:
+		return blueprint.createCommand(commandDropIndex, Map{commandAttrIndex: index.Name})
+	}
+}
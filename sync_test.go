@@ -0,0 +1,215 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSchema_diff(t *testing.T) {
+	type syncCase struct {
+		name     string
+		option   SyncOption
+		sql      []string
+		callback func(table *Blueprint)
+	}
+
+	var (
+		def     = "a"
+		columns = []*ColumnInfo{
+			{Name: "id", Type: "bigint unsigned", AutoIncrement: true},
+			{Name: "name", Type: "varchar(50)", Default: &def, Charset: "utf8mb4", Collation: "utf8mb4_unicode_ci"},
+			{Name: "age", Type: "int(11)", Nullable: true, Comment: "年龄"},
+			{Name: "type", Type: "enum('one','two')"},
+			{Name: "account", Type: "varchar(255)"},
+			{Name: "slug", Type: "varchar(50)", Nullable: true, Generated: "stored", Expression: "lower(`name`)"},
+		}
+		indexes = []*IndexInfo{
+			{Name: "PRIMARY", Columns: []string{"id"}, Unique: true, Primary: true},
+			{Name: "users_account_unique", Columns: []string{"account"}, Unique: true},
+			{Name: "users_age_index", Columns: []string{"age"}},
+		}
+	)
+
+	cases := []syncCase{
+		{
+			name: "Same",
+			callback: func(table *Blueprint) {
+				table.Id()
+				table.String("name", 50).Default("a")
+				table.Int("age").Nullable().Comment("年龄").Index()
+				table.Enum("type", []string{"one", "two"})
+				table.String("account").Unique()
+				table.String("slug", 50).StoredAs("lower(name)").Nullable()
+			},
+		},
+		{
+			name: "Add_Change",
+			sql: []string{
+				"alter table `users` drop `slug`",
+				"alter table `users` add `email` varchar(100) null, add `slug` varchar(50) generated always as (lower(name)) virtual null",
+				"alter table `users` modify `name` varchar(100) character set utf8mb4 collate 'utf8mb4_unicode_ci' not null default 'a', modify `age` int null comment '用户年龄'",
+				"alter table `users` add unique `users_email_unique`(`email`)",
+			},
+			callback: func(table *Blueprint) {
+				table.Id()
+				table.String("name", 100).Default("a")
+				table.Int("age").Nullable().Comment("用户年龄").Index()
+				table.Enum("type", []string{"one", "two"})
+				table.String("account").Unique()
+				table.String("email", 100).Nullable().Unique()
+				table.String("slug", 50).VirtualAs("lower(name)").Nullable()
+			},
+		},
+		{
+			name: "Keep",
+			callback: func(table *Blueprint) {
+				table.Id()
+				table.String("name", 50).Default("a")
+			},
+		},
+		{
+			name:   "Fulltext",
+			option: SyncOption{DropIndexes: true},
+			sql: []string{
+				"alter table `users` drop index `users_age_index`",
+				"alter table `users` add fulltext index `users_name_fulltext`(`name`) with parser ngram",
+				"alter table `users` add fulltext index `users_age_fulltext`(`age`)",
+			},
+			callback: func(table *Blueprint) {
+				table.Id()
+				table.String("name", 50).Default("a").Fulltext(WithParser("ngram"))
+				table.Int("age").Nullable().Comment("年龄").Fulltext()
+				table.String("account").Unique()
+			},
+		},
+		{
+			name:   "Drop",
+			option: SyncOption{DropColumns: true, DropIndexes: true},
+			sql: []string{
+				"alter table `users` drop index `users_age_index`",
+				"alter table `users` drop `type`, drop `account`, drop `slug`",
+				"alter table `users` add index `users_name_age_index`(`name`, `age`)",
+			},
+			callback: func(table *Blueprint) {
+				table.Id()
+				table.String("name", 50).Default("a")
+				table.Int("age").Nullable().Comment("年龄")
+				table.Index([]string{"name", "age"})
+			},
+		},
+	}
+
+	newSchema := NewSchema(context.Background(), &Config{})
+
+	for _, item := range cases {
+		blueprint := NewBlueprint(newSchema, "users", item.callback)
+		if err := newSchema.diff(blueprint, columns, indexes, nil, nil, item.option); err != nil {
+			t.Fatal("diff err:", item.name, err)
+		}
+		sql, err := blueprint.ToSql(newSchema.GetGrammar())
+		if err != nil {
+			t.Fatal("ToSql err:", item.name, err)
+		}
+		if len(sql) != len(item.sql) {
+			t.Fatal("ToSql err:", item.name, "\nsql:", item.sql, "\ngen:", sql)
+		}
+		for i, s := range item.sql {
+			if sql[i] != s {
+				t.Fatal("ToSql err:", item.name, "\nsql:", item.sql, "\ngen:", sql)
+			}
+		}
+	}
+
+	// the stored column is dropped and added again as a virtual column
+	blueprint := NewBlueprint(newSchema, "users", func(table *Blueprint) {
+		table.Id()
+		table.String("slug", 50).VirtualAs("lower(name)").Nullable()
+	})
+	if err := newSchema.diff(blueprint, columns, indexes, nil, nil, SyncOption{}); err != nil {
+		t.Fatal("diff err: regenerated", err)
+	}
+	statements, err := blueprint.toStatements(newSchema.GetGrammar())
+	if err != nil || len(statements) != 2 || statements[0].SQL != "alter table `users` drop `slug`" || !statements[0].Destructive {
+		t.Fatal("diff err: regenerated", statements, err)
+	}
+
+	blueprint = NewBlueprint(newSchema, "users", func(table *Blueprint) {
+		table.Primary([]string{"id", "name"})
+	})
+	if err := newSchema.diff(blueprint, columns, indexes, nil, nil, SyncOption{}); err == nil {
+		t.Fatal("diff err: the changed primary key is dropped without DropIndexes")
+	}
+}
+
+func TestSchema_Sync_checks(t *testing.T) {
+	var (
+		checks [][]driver.Value
+		db     = &testDriver{}
+	)
+
+	db.onExec = func(query string, args []driver.NamedValue) {
+		// the added checks are reported by the next sync
+		if name := regexp.MustCompile("add constraint `(\\w+)` check \\((.*)\\)$").FindStringSubmatch(query); name != nil {
+			checks = append(checks, []driver.Value{name[1], "(" + name[2] + ")"})
+		}
+	}
+	db.onQuery = func(query string, args []driver.NamedValue) (driver.Rows, error) {
+		switch {
+		case strings.Contains(query, "information_schema.tables"):
+			return &testRows{columns: []string{"table_name"}, values: [][]driver.Value{{"users"}}}, nil
+		case strings.Contains(query, "information_schema.columns"):
+			return &testRows{
+				columns: []string{"name", "type_name", "type", "length", "nullable", "default", "charset", "collation",
+					"comment", "auto_increment", "position", "on_update", "generated", "expression"},
+				values: [][]driver.Value{
+					{"id", "bigint", "bigint unsigned", nil, int64(0), nil, nil, nil, "", int64(1), int64(1), nil, nil, nil},
+					{"age", "int", "int", nil, int64(0), nil, nil, nil, "", int64(0), int64(2), nil, nil, nil},
+					{"score", "int", "int", nil, int64(0), nil, nil, nil, "", int64(0), int64(3), nil, nil, nil},
+				},
+			}, nil
+		case strings.Contains(query, "check_constraints"):
+			return &testRows{columns: []string{"name", "expression"}, values: append([][]driver.Value(nil), checks...)}, nil
+		default:
+			return &testRows{}, nil
+		}
+	}
+
+	newSchema := NewSchema(context.Background(), &Config{DB: sql.OpenDB(db), Database: "app"})
+	sync := func(expression string) []string {
+		db.executed = nil
+		err := newSchema.Sync("users", func(table *Blueprint) {
+			table.Id()
+			table.Int("age").Check("`age` >= 18")
+			table.Int("score")
+			table.Check("users_score_check", expression)
+		})
+		if err != nil {
+			t.Fatal("Sync err:", err)
+		}
+		return db.executed
+	}
+
+	// the existing check of an unchanged column is kept, the missing checks are added once
+	checks = [][]driver.Value{{"users_age_check", "(`age` >= 18)"}}
+	expected := []string{"alter table `users` add constraint `users_score_check` check (`score` > 0)"}
+	if executed := sync("`score` > 0"); fmt.Sprint(executed) != fmt.Sprint(expected) {
+		t.Fatal("Sync err: first", executed)
+	}
+	if executed := sync("score > 0"); len(executed) != 0 {
+		t.Fatal("Sync err: second", executed)
+	}
+
+	// the changed check is dropped and added again
+	expected = []string{
+		"alter table `users` drop check `users_score_check`",
+		"alter table `users` add constraint `users_score_check` check (`score` >= 0)",
+	}
+	if executed := sync("`score` >= 0"); fmt.Sprint(executed) != fmt.Sprint(expected) {
+		t.Fatal("Sync err: changed", executed)
+	}
+}
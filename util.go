@@ -104,6 +104,17 @@ func arrMap[V interface{}, T []V](arr T, callback func(v V) V) T {
 	return items
 }
 
+// toStrings converts a string or []string to []string
+func toStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	}
+	return nil
+}
+
 // ReplaceByArray returns a copy of `origin`,
 // which is replaced by a slice in order, case-sensitively.
 func replaceByArray(origin string, array []string) string {
@@ -224,3 +235,75 @@ func isLetterLower(b byte) bool {
 	}
 	return false
 }
+
+// versionCompare compares two dotted version strings like 8.0.33, returns -1, 0 or 1.
+// Non-numeric suffixes such as "-MariaDB" or "-log" are ignored.
+func versionCompare(a, b string) int {
+	parse := func(version string) []int {
+		var parts []int
+		for _, part := range strings.Split(version, ".") {
+			end := 0
+			for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+				end++
+			}
+			n, _ := strconv.Atoi(part[:end])
+			parts = append(parts, n)
+			if end < len(part) {
+				break
+			}
+		}
+		return parts
+	}
+
+	x, y := parse(a), parse(b)
+	for i := 0; i < len(x) || i < len(y); i++ {
+		var m, n int
+		if i < len(x) {
+			m = x[i]
+		}
+		if i < len(y) {
+			n = y[i]
+		}
+		if m != n {
+			return ternary(m < n, -1, 1)
+		}
+	}
+
+	return 0
+}
+
+// snake converts a camel case string to snake case, e.g. UserProfile to user_profile
+func snake(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'A' && c <= 'Z' {
+			// start a new word before an upper case letter, except inside an acronym like ID
+			if i > 0 && (isLetterLower(s[i-1]) || (i+1 < len(s) && isLetterLower(s[i+1]) && s[i-1] != '_')) {
+				b.WriteByte('_')
+			}
+			c += 32
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// plural returns the english plural form of a word, e.g. user to users, category to categories
+func plural(s string) string {
+	if s == "" {
+		return s
+	}
+
+	switch {
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsRune("aeiou", rune(s[len(s)-2])):
+		return s[:len(s)-1] + "ies"
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "z"),
+		strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	}
+
+	return s + "s"
+}